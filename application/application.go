@@ -1,101 +1,210 @@
 package application
 
 import (
+	"context"
+	"os"
+	"time"
+
 	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/commands"
 	"github.com/kaellybot/kaelly-portals/models/constants"
-	areaRepo "github.com/kaellybot/kaelly-portals/repositories/areas"
-	dimensionRepo "github.com/kaellybot/kaelly-portals/repositories/dimensions"
-	serverRepo "github.com/kaellybot/kaelly-portals/repositories/servers"
-	subAreaRepo "github.com/kaellybot/kaelly-portals/repositories/subareas"
-	transportRepo "github.com/kaellybot/kaelly-portals/repositories/transports"
-	"github.com/kaellybot/kaelly-portals/services/areas"
-	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	deadLetterRepo "github.com/kaellybot/kaelly-portals/repositories/deadletters"
+	snapshotRepo "github.com/kaellybot/kaelly-portals/repositories/snapshots"
+	subscriptionRepo "github.com/kaellybot/kaelly-portals/repositories/subscriptions"
+	"github.com/kaellybot/kaelly-portals/services/admins"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/pollers"
 	"github.com/kaellybot/kaelly-portals/services/portals"
-	"github.com/kaellybot/kaelly-portals/services/servers"
-	"github.com/kaellybot/kaelly-portals/services/subareas"
-	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/services/references"
+	"github.com/kaellybot/kaelly-portals/services/reports"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
+	"github.com/kaellybot/kaelly-portals/services/statistics"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
 	"github.com/kaellybot/kaelly-portals/utils/databases"
 	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/kaellybot/kaelly-portals/utils/retries"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 )
 
-func New() (*Impl, error) {
+// New builds the application; probes are served straight away when serveProbes is set,
+// to report the startup state while MySQL is being reached.
+func New(config configs.Config, serveProbes bool) (*Impl, error) {
 	// misc
-	broker := amqp.New(constants.RabbitMQClientID, viper.GetString(constants.RabbitMQAddress),
-		amqp.WithBindings(portals.GetBinding()))
-	db := databases.New()
-	if err := db.Run(); err != nil {
+	traces, err := insights.NewTraces(config)
+	if err != nil {
 		return nil, err
 	}
 
-	probes := insights.NewProbes(broker.IsConnected, db.IsConnected)
-	prom := insights.NewPrometheusMetrics()
+	broker := amqp.New(constants.RabbitMQClientID, config.RabbitMQAddress,
+		amqp.WithBindings(portals.GetBindings(config)...))
+	db := databases.New(config)
+	repos := newReferenceRepositories(db)
+	fallback := newReferenceFallback(repos, db.IsConnected)
+	probes := insights.NewProbes(config, fallback.IsDegraded, broker.IsConnected, fallback.IsReady)
+	if serveProbes {
+		probes.ListenAndServe()
+	}
+	errDB := db.Run()
+	prom := insights.NewPrometheusMetrics(config)
 
 	// repositories
-	serverRepo := serverRepo.New(db)
-	dimensionRepo := dimensionRepo.New(db)
-	areaRepo := areaRepo.New(db)
-	subAreaRepo := subAreaRepo.New(db)
-	transportRepo := transportRepo.New(db)
+	var deadLetterRepo deadLetterRepo.Repository = deadLetterRepo.New(db)
+	var subscriptionRepo subscriptionRepo.Repository = subscriptionRepo.New(db)
+	snapshotRepo := snapshotRepo.New(db)
 
 	// services
-	serverService, err := servers.New(serverRepo)
+	referenceService := references.New(repos.servers, repos.dimensions, repos.areas, repos.subAreas,
+		repos.transports, repos.bounds, repos.labels)
+	refs, degraded, err := loadReferenceServices(fallback, referenceService, errDB,
+		config.ReferenceSource, config.ReferenceSnapshotFile)
 	if err != nil {
 		return nil, err
 	}
-
-	dimensionService, err := dimensions.New(dimensionRepo)
-	if err != nil {
-		return nil, err
+	if degraded {
+		deadLetterRepo = offlineDeadLetters{deadLetterRepo, refs.fallback}
+		subscriptionRepo = offlineSubscriptions{subscriptionRepo}
 	}
 
-	areaService, err := areas.New(areaRepo)
+	deadLetterService, err := deadletters.New(config, deadLetterRepo)
 	if err != nil {
 		return nil, err
 	}
+	if refs.fallback != nil {
+		// Dead letters are loaded from MySQL along with reference data, once reachable again.
+		refs.fallback.reloaders = append(refs.fallback.reloaders, deadLetterService)
+	}
 
-	subAreaService, err := subareas.New(subAreaRepo)
+	subscriptionService, err := subscriptions.New(broker, subscriptionRepo)
 	if err != nil {
 		return nil, err
 	}
 
-	transportService, err := transports.New(transportRepo)
+	confidenceService := confidences.New(config.PositionMaxAge, refs.bounds)
+	snapshotService := snapshots.New(snapshotRepo)
+	statisticService := statistics.New(snapshotRepo)
+	reportService := reports.New(refs.servers, refs.dimensions, refs.bounds)
+	portals, err := portals.New(broker, config, refs.servers, refs.dimensions,
+		refs.areas, refs.subAreas, refs.transports, deadLetterService, confidenceService,
+		snapshotService, refs.bounds)
 	if err != nil {
 		return nil, err
 	}
 
-	portals, err := portals.New(broker, serverService, dimensionService,
-		areaService, subAreaService, transportService)
-	if err != nil {
-		return nil, err
+	poller := pollers.New(config.PollInterval, portals, subscriptionService)
+	admin := admins.New(portals, poller, refs.servers, deadLetterService, subscriptionService,
+		refs.reloaders()...)
+	var adminConsumer amqp.MessageConsumer
+	if config.AdminToken != "" {
+		adminConsumer = admins.NewConsumer(admin, broker, config.AdminToken)
 	}
+	api := insights.NewAPI(config, portals.GetPortals,
+		func(position *amqp.PortalPositionAnswer_PortalPosition) (float64, bool, bool) {
+			confidence := confidenceService.Score(position)
+			return confidence.Score, confidence.Outdated, confidence.Suspicious
+		}, snapshotService.GetLeaderboard, statisticService.GetStatistics, refs.labels.Localize,
+		admins.NewHandler(admin, config.AdminToken))
+	commands := commands.New(os.Stdout, config, broker, portals, refs.servers, refs.dimensions,
+		refs.areas, refs.subAreas, refs.transports, deadLetterService,
+		subscriptionService, confidenceService, snapshotService, statisticService, reportService, refs.bounds,
+		refs.labels, referenceService, repos.servers, repos.dimensions, repos.areas, repos.subAreas, repos.transports)
 
 	return &Impl{
-		portals: portals,
-		broker:  broker,
-		db:      db,
-		probes:  probes,
-		prom:    prom,
+		portals:  portals,
+		poller:   poller,
+		commands: commands,
+		broker:   broker,
+		db:       db,
+		probes:   probes,
+		prom:     prom,
+		api:      api,
+		traces:   traces,
+
+		deadLetters: deadLetterService,
+		references:  refs.fallback,
+		retryPolicy: retries.Policy{Retries: config.StartupRetries, Delay: config.StartupRetryDelay},
+		gracePeriod: config.ShutdownGracePeriod,
+
+		adminQueue:    config.AdminQueue,
+		adminConsumer: adminConsumer,
 	}, nil
 }
 
 func (app *Impl) Run() error {
-	app.probes.ListenAndServe()
 	app.prom.ListenAndServe()
+	app.api.ListenAndServe()
 
+	// The broker keeps on reconnecting by itself after a first failure.
 	if err := app.broker.Run(); err != nil {
-		return err
+		err = retries.Do("RabbitMQ", app.retryPolicy, func() error {
+			if app.broker.IsConnected() {
+				return nil
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	app.portals.Consume()
+	if app.adminConsumer != nil {
+		log.Info().Str(constants.LogQueue, app.adminQueue).Msgf("Consuming admin commands...")
+		app.broker.Consume(app.adminQueue, app.adminConsumer)
+	}
+	app.poller.Start()
+	app.deadLetters.Start()
+	if app.references != nil {
+		app.references.Start()
+	}
+	app.probes.SetStarted()
 	return nil
 }
 
+func (app *Impl) Reload(runtime configs.Runtime) {
+	app.portals.Reload(runtime)
+	app.poller.Reload(runtime.PollInterval)
+}
+
+func (app *Impl) Execute(args []string) error {
+	return app.commands.Execute(context.Background(), args)
+}
+
+// Shutdown stops taking new work first, waits for in-flight requests within the grace period,
+// and only then closes connections, so that requests being treated can still be answered.
 func (app *Impl) Shutdown() {
-	app.broker.Shutdown()
-	app.db.Shutdown()
-	app.prom.Shutdown()
-	app.probes.Shutdown()
-	log.Info().Msgf("Application is no longer running")
+	start := time.Now()
+	shutdownStep("poller", app.poller.Stop)
+	shutdownStep("dead letter purge", app.deadLetters.Stop)
+	if app.references != nil {
+		shutdownStep("reference reconnection", app.references.Stop)
+	}
+	shutdownStep("consumer", app.portals.Stop)
+	shutdownStep("readiness", func() { app.probes.SetReady(false) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), app.gracePeriod)
+	defer cancel()
+	shutdownStep("portal requests", func() {
+		if err := app.portals.Drain(ctx); err != nil {
+			log.Warn().Err(err).Msgf("Grace period elapsed, abandoning portal requests being treated")
+		}
+	})
+	shutdownStep("api", func() { app.api.Shutdown(ctx) })
+
+	shutdownStep("broker", app.broker.Shutdown)
+	shutdownStep("database", app.db.Shutdown)
+	shutdownStep("metrics", app.prom.Shutdown)
+	shutdownStep("probes", app.probes.Shutdown)
+	shutdownStep("traces", app.traces.Shutdown)
+	log.Info().Dur(constants.LogDuration, time.Since(start)).Msgf("Application is no longer running")
+}
+
+func shutdownStep(name string, step func()) {
+	start := time.Now()
+	step()
+	log.Info().
+		Str(constants.LogStep, name).
+		Dur(constants.LogDuration, time.Since(start)).
+		Msgf("Shutdown step done")
 }
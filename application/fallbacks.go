@@ -0,0 +1,172 @@
+package application
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/rs/zerolog/log"
+)
+
+// newReferenceFallback serves reference data from the snapshot file until MySQL repositories are
+// known to be reachable, which is the case as soon as reference data is loaded from them.
+func newReferenceFallback(mysql referenceRepositories, isConnected func() bool) *referenceFallback {
+	return &referenceFallback{
+		mysql:       mysql,
+		isConnected: isConnected,
+	}
+}
+
+// repositories returns the repositories to build reference services on, switching
+// from the snapshot file to MySQL once reachable again.
+func (fallback *referenceFallback) repositories() referenceRepositories {
+	return referenceRepositories{
+		servers:    fallback,
+		dimensions: fallback,
+		areas:      fallback,
+		subAreas:   fallback,
+		transports: fallback,
+		bounds:     fallback,
+		labels:     fallback,
+	}
+}
+
+func (fallback *referenceFallback) current() referenceRepositories {
+	if fallback.online.Load() {
+		return fallback.mysql
+	}
+
+	return fallback.file
+}
+
+// Start checks in background whether MySQL is reachable again, to reload reference data
+// from it; it stops once done or when Stop is called.
+func (fallback *referenceFallback) Start() {
+	fallback.stop = make(chan struct{})
+	fallback.done = make(chan struct{})
+	log.Info().Msgf("Checking every %v whether MySQL is reachable again...", referenceReconnectInterval)
+
+	go func() {
+		defer close(fallback.done)
+		ticker := time.NewTicker(referenceReconnectInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-fallback.stop:
+				return
+			case <-ticker.C:
+				if fallback.switchBack() {
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Stop waits for the running check, if any, to complete.
+func (fallback *referenceFallback) Stop() {
+	if fallback.stop == nil {
+		return
+	}
+
+	close(fallback.stop)
+	<-fallback.done
+	fallback.stop = nil
+}
+
+// ServesSnapshot tells if reference data is served from the snapshot file rather than from MySQL.
+func (fallback *referenceFallback) ServesSnapshot() bool {
+	return !fallback.online.Load()
+}
+
+// IsReady tells if reference data can be served, either from MySQL or from the snapshot file.
+func (fallback *referenceFallback) IsReady() bool {
+	return fallback.ServesSnapshot() || fallback.isConnected()
+}
+
+// IsDegraded tells if MySQL is unreachable while reference data is served from the snapshot file.
+func (fallback *referenceFallback) IsDegraded() bool {
+	return fallback.ServesSnapshot() && !fallback.isConnected()
+}
+
+// switchBack reloads reference data from MySQL if reachable; true is returned once done.
+// If any reference data cannot be reloaded, the snapshot file is used again until next check.
+func (fallback *referenceFallback) switchBack() bool {
+	if !fallback.isConnected() {
+		return false
+	}
+
+	fallback.online.Store(true)
+	errs := make([]error, 0)
+	for _, reloader := range fallback.reloaders {
+		if err := reloader.Reload(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		fallback.online.Store(false)
+		log.Warn().Err(err).Msgf("Cannot reload reference data from MySQL, still serving the snapshot")
+		return false
+	}
+
+	log.Info().Msgf("MySQL reachable again, reference data reloaded from it")
+	return true
+}
+
+func (fallback *referenceFallback) GetServers() ([]entities.Server, error) {
+	return fallback.current().servers.GetServers()
+}
+
+func (fallback *referenceFallback) SaveServer(server entities.Server) error {
+	return fallback.current().servers.SaveServer(server)
+}
+
+func (fallback *referenceFallback) GetDimensions() ([]entities.Dimension, error) {
+	return fallback.current().dimensions.GetDimensions()
+}
+
+func (fallback *referenceFallback) SaveDimension(dimension entities.Dimension) error {
+	return fallback.current().dimensions.SaveDimension(dimension)
+}
+
+func (fallback *referenceFallback) GetAreas() ([]entities.Area, error) {
+	return fallback.current().areas.GetAreas()
+}
+
+func (fallback *referenceFallback) SaveArea(area entities.Area) error {
+	return fallback.current().areas.SaveArea(area)
+}
+
+func (fallback *referenceFallback) GetSubAreas() ([]entities.SubArea, error) {
+	return fallback.current().subAreas.GetSubAreas()
+}
+
+func (fallback *referenceFallback) SaveSubArea(subArea entities.SubArea) error {
+	return fallback.current().subAreas.SaveSubArea(subArea)
+}
+
+func (fallback *referenceFallback) GetTransportTypes() ([]entities.TransportType, error) {
+	return fallback.current().transports.GetTransportTypes()
+}
+
+func (fallback *referenceFallback) SaveTransportType(transportType entities.TransportType) error {
+	return fallback.current().transports.SaveTransportType(transportType)
+}
+
+func (fallback *referenceFallback) GetMapBounds() ([]entities.MapBounds, error) {
+	return fallback.current().bounds.GetMapBounds()
+}
+
+func (fallback *referenceFallback) SaveMapBounds(mapBounds entities.MapBounds) error {
+	return fallback.current().bounds.SaveMapBounds(mapBounds)
+}
+
+func (fallback *referenceFallback) GetLabels() ([]entities.Label, error) {
+	return fallback.current().labels.GetLabels()
+}
+
+func (fallback *referenceFallback) SaveLabel(label entities.Label) error {
+	return fallback.current().labels.SaveLabel(label)
+}
@@ -0,0 +1,201 @@
+package application
+
+import (
+	"errors"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	areaRepo "github.com/kaellybot/kaelly-portals/repositories/areas"
+	boundsRepo "github.com/kaellybot/kaelly-portals/repositories/bounds"
+	deadLetterRepo "github.com/kaellybot/kaelly-portals/repositories/deadletters"
+	dimensionRepo "github.com/kaellybot/kaelly-portals/repositories/dimensions"
+	labelRepo "github.com/kaellybot/kaelly-portals/repositories/labels"
+	referenceRepo "github.com/kaellybot/kaelly-portals/repositories/references"
+	serverRepo "github.com/kaellybot/kaelly-portals/repositories/servers"
+	subAreaRepo "github.com/kaellybot/kaelly-portals/repositories/subareas"
+	subscriptionRepo "github.com/kaellybot/kaelly-portals/repositories/subscriptions"
+	transportRepo "github.com/kaellybot/kaelly-portals/repositories/transports"
+	"github.com/kaellybot/kaelly-portals/services/admins"
+	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/labels"
+	"github.com/kaellybot/kaelly-portals/services/references"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/subareas"
+	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+	"github.com/rs/zerolog/log"
+)
+
+// offlineDeadLetters starts without dead letters when MySQL is unreachable at startup;
+// writes still reach MySQL, failing until it is back. Once reference data is served from
+// MySQL again, dead letters are read from it as well.
+type offlineDeadLetters struct {
+	deadLetterRepo.Repository
+	fallback *referenceFallback
+}
+
+// offlineSubscriptions considers there is no subscription while MySQL is unreachable,
+// subscriptions being loaded again at each poll.
+type offlineSubscriptions struct {
+	subscriptionRepo.Repository
+}
+
+func (repo offlineDeadLetters) GetDeadLetters() ([]entities.DeadLetter, error) {
+	if repo.fallback != nil && !repo.fallback.ServesSnapshot() {
+		return repo.Repository.GetDeadLetters()
+	}
+
+	return nil, nil
+}
+
+func (repo offlineSubscriptions) GetSubscriptions() ([]entities.Subscription, error) {
+	subscriptions, err := repo.Repository.GetSubscriptions()
+	if err != nil {
+		log.Warn().Err(err).Msgf("Cannot load subscriptions while MySQL is unreachable, continuing without")
+		return nil, nil
+	}
+
+	return subscriptions, nil
+}
+
+func newReferenceRepositories(db databases.MySQLConnection) referenceRepositories {
+	return referenceRepositories{
+		servers:    serverRepo.New(db),
+		dimensions: dimensionRepo.New(db),
+		areas:      areaRepo.New(db),
+		subAreas:   subAreaRepo.New(db),
+		transports: transportRepo.New(db),
+		bounds:     boundsRepo.New(db),
+		labels:     labelRepo.New(db),
+	}
+}
+
+func newFileReferenceRepositories(repo referenceRepo.Repository) referenceRepositories {
+	return referenceRepositories{
+		servers:    repo,
+		dimensions: repo,
+		areas:      repo,
+		subAreas:   repo,
+		transports: repo,
+		bounds:     repo,
+		labels:     repo,
+	}
+}
+
+// loadReferenceServices loads reference data from MySQL and keeps a copy of it in the snapshot
+// file if any. If MySQL cannot be reached, reference data is loaded from this file instead,
+// the application being then degraded until MySQL is reachable again (see referenceFallback).
+// With the file source, reference data is always loaded from the snapshot file, and the
+// application is degraded only if MySQL is unreachable.
+func loadReferenceServices(fallback *referenceFallback, referenceService references.Service, errDB error,
+	source, snapshotFile string) (*referenceServices, bool, error) {
+	if source == constants.ReferenceSourceFile {
+		fileRepos, err := loadFileReferenceRepositories(snapshotFile)
+		if err != nil {
+			return nil, false, err
+		}
+
+		refs, err := newReferenceServices(fileRepos)
+		return refs, errDB != nil, err
+	}
+
+	if errDB == nil {
+		refs, err := newReferenceServices(fallback.mysql)
+		if err == nil {
+			fallback.online.Store(true)
+			if snapshotFile != "" {
+				if _, errExport := referenceService.Export(snapshotFile); errExport != nil {
+					log.Error().Err(errExport).Msgf("Cannot save reference snapshot, keeping the previous one")
+				}
+			}
+			return refs, false, nil
+		}
+		errDB = err
+	}
+
+	if snapshotFile == "" {
+		return nil, false, errDB
+	}
+
+	log.Warn().Err(errDB).
+		Str(constants.LogFileName, snapshotFile).
+		Msgf("MySQL unreachable, serving reference data from the last snapshot")
+	fileRepos, err := loadFileReferenceRepositories(snapshotFile)
+	if err != nil {
+		return nil, false, errors.Join(errDB, err)
+	}
+
+	fallback.file = fileRepos
+	refs, err := newReferenceServices(fallback.repositories())
+	if err != nil {
+		return nil, false, errors.Join(errDB, err)
+	}
+
+	refs.fallback = fallback
+	fallback.reloaders = refs.reloaders()
+	return refs, true, nil
+}
+
+func loadFileReferenceRepositories(snapshotFile string) (referenceRepositories, error) {
+	fileRepo := referenceRepo.New(snapshotFile)
+	if err := fileRepo.Load(); err != nil {
+		return referenceRepositories{}, err
+	}
+
+	return newFileReferenceRepositories(fileRepo), nil
+}
+
+func newReferenceServices(repos referenceRepositories) (*referenceServices, error) {
+	serverService, err := servers.New(repos.servers)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensionService, err := dimensions.New(repos.dimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	areaService, err := areas.New(repos.areas)
+	if err != nil {
+		return nil, err
+	}
+
+	subAreaService, err := subareas.New(repos.subAreas)
+	if err != nil {
+		return nil, err
+	}
+
+	transportService, err := transports.New(repos.transports)
+	if err != nil {
+		return nil, err
+	}
+
+	boundsService, err := bounds.New(repos.bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	labelService, err := labels.New(repos.labels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &referenceServices{
+		servers:    serverService,
+		dimensions: dimensionService,
+		areas:      areaService,
+		subAreas:   subAreaService,
+		transports: transportService,
+		bounds:     boundsService,
+		labels:     labelService,
+	}, nil
+}
+
+// reloaders lists the reference services that admins can load again.
+func (refs *referenceServices) reloaders() []admins.Reloader {
+	return []admins.Reloader{refs.servers, refs.dimensions, refs.areas, refs.subAreas,
+		refs.transports, refs.bounds, refs.labels}
+}
@@ -1,21 +1,102 @@
 package application
 
 import (
+	"sync/atomic"
+	"time"
+
 	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/commands"
+	areaRepo "github.com/kaellybot/kaelly-portals/repositories/areas"
+	boundsRepo "github.com/kaellybot/kaelly-portals/repositories/bounds"
+	dimensionRepo "github.com/kaellybot/kaelly-portals/repositories/dimensions"
+	labelRepo "github.com/kaellybot/kaelly-portals/repositories/labels"
+	serverRepo "github.com/kaellybot/kaelly-portals/repositories/servers"
+	subAreaRepo "github.com/kaellybot/kaelly-portals/repositories/subareas"
+	transportRepo "github.com/kaellybot/kaelly-portals/repositories/transports"
+	"github.com/kaellybot/kaelly-portals/services/admins"
+	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/labels"
+	"github.com/kaellybot/kaelly-portals/services/pollers"
 	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/subareas"
+	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
 	"github.com/kaellybot/kaelly-portals/utils/databases"
 	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/kaellybot/kaelly-portals/utils/retries"
 )
 
+// referenceReconnectInterval is the time between two checks of MySQL while reference data
+// is served from the snapshot file.
+const referenceReconnectInterval = 30 * time.Second
+
 type Application interface {
 	Run() error
+	Execute(args []string) error
+	Reload(runtime configs.Runtime)
 	Shutdown()
 }
 
 type Impl struct {
-	portals portals.Service
-	broker  amqp.MessageBroker
-	db      databases.MySQLConnection
-	probes  insights.Probes
-	prom    insights.PrometheusMetrics
+	portals  portals.Service
+	poller   pollers.Service
+	commands commands.Command
+	broker   amqp.MessageBroker
+	db       databases.MySQLConnection
+	probes   insights.Probes
+	prom     insights.PrometheusMetrics
+	api      insights.API
+	traces   insights.Traces
+	// deadLetters purges outdated dead letters in background.
+	deadLetters deadletters.Service
+	// references switches reference data back to MySQL in background, nil if not served from the snapshot.
+	references *referenceFallback
+	// retryPolicy applies to the initial RabbitMQ connection.
+	retryPolicy retries.Policy
+	// gracePeriod bounds the time spent waiting for in-flight requests on shutdown.
+	gracePeriod time.Duration
+	// adminConsumer treats the admin commands of adminQueue, nil if admin commands are disabled.
+	adminQueue    string
+	adminConsumer amqp.MessageConsumer
+}
+
+// referenceRepositories gathers the repositories of reference data, either backed
+// by MySQL or by a local snapshot file.
+type referenceRepositories struct {
+	servers    serverRepo.Repository
+	dimensions dimensionRepo.Repository
+	areas      areaRepo.Repository
+	subAreas   subAreaRepo.Repository
+	transports transportRepo.Repository
+	bounds     boundsRepo.Repository
+	labels     labelRepo.Repository
+}
+
+// referenceFallback serves reference data from the snapshot file while MySQL is unreachable,
+// and from MySQL once it is back. It also tells the probes whether MySQL is required.
+type referenceFallback struct {
+	mysql       referenceRepositories
+	file        referenceRepositories
+	online      atomic.Bool
+	isConnected func() bool
+	reloaders   []admins.Reloader
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// referenceServices gathers the services holding reference data loaded at startup.
+type referenceServices struct {
+	servers    servers.Service
+	dimensions dimensions.Service
+	areas      areas.Service
+	subAreas   subareas.Service
+	transports transports.Service
+	bounds     bounds.Service
+	labels     labels.Service
+	// fallback is set when reference data is served from the snapshot file because MySQL is unreachable.
+	fallback *referenceFallback
 }
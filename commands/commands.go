@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	areaRepo "github.com/kaellybot/kaelly-portals/repositories/areas"
+	dimensionRepo "github.com/kaellybot/kaelly-portals/repositories/dimensions"
+	serverRepo "github.com/kaellybot/kaelly-portals/repositories/servers"
+	subAreaRepo "github.com/kaellybot/kaelly-portals/repositories/subareas"
+	transportRepo "github.com/kaellybot/kaelly-portals/repositories/transports"
+	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/labels"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/references"
+	"github.com/kaellybot/kaelly-portals/services/reports"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
+	"github.com/kaellybot/kaelly-portals/services/statistics"
+	"github.com/kaellybot/kaelly-portals/services/subareas"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+func New(out io.Writer, config configs.Config, broker amqp.MessageBroker, portalService portals.Service,
+	serverService servers.Service, dimensionService dimensions.Service, areaService areas.Service,
+	subAreaService subareas.Service, transportService transports.Service, deadLetterService deadletters.Service,
+	subscriptionService subscriptions.Service, confidenceService confidences.Service,
+	snapshotService snapshots.Service, statisticService statistics.Service, reportService reports.Service,
+	boundsService bounds.Service, labelService labels.Service, referenceService references.Service,
+	serverRepo serverRepo.Repository, dimensionRepo dimensionRepo.Repository,
+	areaRepo areaRepo.Repository, subAreaRepo subAreaRepo.Repository,
+	transportRepo transportRepo.Repository) *Impl {
+	return &Impl{
+		out:                 out,
+		config:              config,
+		broker:              broker,
+		portalService:       portalService,
+		serverService:       serverService,
+		dimensionService:    dimensionService,
+		areaService:         areaService,
+		subAreaService:      subAreaService,
+		transportService:    transportService,
+		deadLetterService:   deadLetterService,
+		subscriptionService: subscriptionService,
+		confidenceService:   confidenceService,
+		snapshotService:     snapshotService,
+		statisticService:    statisticService,
+		reportService:       reportService,
+		boundsService:       boundsService,
+		labelService:        labelService,
+		referenceService:    referenceService,
+		serverRepo:          serverRepo,
+		dimensionRepo:       dimensionRepo,
+		areaRepo:            areaRepo,
+		subAreaRepo:         subAreaRepo,
+		transportRepo:       transportRepo,
+	}
+}
+
+// IsCommand returns true if the program arguments ask for a known operator command
+// instead of starting the consumer.
+func IsCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case fetchCommand, checkMappingsCommand, syncReferenceCommand, replayCommand,
+		deadLettersCommand, requeueCommand, subscribeCommand, unsubscribeCommand,
+		subscriptionsCommand, leaderboardCommand, statisticsCommand, reportCommand,
+		exportRefsCommand, importRefsCommand:
+		return true
+	default:
+		return false
+	}
+}
+
+func (command *Impl) Execute(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return command.usage(errBadArguments)
+	}
+
+	name, params := args[0], args[1:]
+	switch name {
+	case fetchCommand:
+		return command.fetch(ctx, params)
+	case checkMappingsCommand:
+		return command.checkMappings(ctx, params)
+	case syncReferenceCommand:
+		return command.syncReference(ctx, params)
+	case replayCommand:
+		return command.replay(ctx, params)
+	case deadLettersCommand:
+		return command.listDeadLetters(ctx, params)
+	case requeueCommand:
+		return command.requeue(ctx, params)
+	case subscribeCommand:
+		return command.subscribe(ctx, params)
+	case unsubscribeCommand:
+		return command.unsubscribe(ctx, params)
+	case subscriptionsCommand:
+		return command.listSubscriptions(ctx, params)
+	case leaderboardCommand:
+		return command.leaderboard(ctx, params)
+	case statisticsCommand:
+		return command.statistics(ctx, params)
+	case reportCommand:
+		return command.report(ctx, params)
+	case exportRefsCommand:
+		return command.exportReferences(ctx, params)
+	case importRefsCommand:
+		return command.importReferences(ctx, params)
+	default:
+		return command.usage(fmt.Errorf("%w: %s", errUnknownCommand, name))
+	}
+}
+
+func (command *Impl) usage(err error) error {
+	fmt.Fprintf(command.out, usage, constants.InternalName)
+	return err
+}
@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/mocks/brokers"
+	mockdeadletters "github.com/kaellybot/kaelly-portals/mocks/deadletters"
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	mocksnapshots "github.com/kaellybot/kaelly-portals/mocks/snapshots"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/labels"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+const (
+	token = "token"
+
+	replayRecord = `{"correlationId": "replayed", "replyTo": "requester", "message": ` +
+		`{"type": "PORTAL_POSITION_REQUEST", "portalPositionRequest": {"serverId": "1", "dimensionId": "enu"}}}`
+)
+
+// newTestCommand wires the commands on top of a fake dofus-portals server and in-memory
+// reference data: server "orukam" and dimension "xelorium" are unmapped, and "orukam"
+// is already used as an internal server ID.
+func newTestCommand(t *testing.T) (*Impl, *bytes.Buffer) {
+	t.Helper()
+	fake := mockportals.New(token)
+	t.Cleanup(fake.Close)
+	fake.SetServers(dofusportals.Server{Id: "agride"}, dofusportals.Server{Id: "orukam"},
+		dofusportals.Server{Id: "draconiros"})
+	fake.SetDimensions(dofusportals.Dimension{Id: "enutrosor"}, dofusportals.Dimension{Id: "xelorium"})
+	remainingUses := float32(42)
+	fake.SetPortals(dofusportals.Portal{Server: "agride", Dimension: "enutrosor", RemainingUses: &remainingUses,
+		Position: &dofusportals.Position{X: 5, Y: -18, Transport: &dofusportals.Transport{
+			Area: "astrub", SubArea: "cite_astrub", Type: dofusportals.Zaap, X: 5, Y: -18}}})
+
+	serverRepo := references.Servers{{ID: "1", DofusPortalsID: "agride"}, {ID: "orukam", DofusPortalsID: "ily"}}
+	dimensionRepo := references.Dimensions{{ID: "enu", DofusPortalsID: "enutrosor"}}
+	areaRepo := references.Areas{{ID: "area-astrub", DofusPortalsID: "astrub"}}
+	subAreaRepo := references.SubAreas{{ID: "subarea-astrub", DofusPortalsID: "cite_astrub"}}
+	transportRepo := references.TransportTypes{{ID: "transport-zaap", DofusPortalsID: "zaap"}}
+	refs := references.New(serverRepo, dimensionRepo, areaRepo, subAreaRepo, transportRepo)
+
+	config := configs.Config{
+		DofusPortalsEnabled:    true,
+		DofusPortalsURL:        fake.URL,
+		DofusPortalsToken:      token,
+		HTTPTimeout:            time.Second,
+		HTTPMode:               constants.HTTPModeLive,
+		DeduplicationTTL:       time.Minute,
+		DeadLetterMaxAttempts:  1,
+		DeadLetterRetention:    time.Hour,
+		DeadLetterMaxEntries:   1,
+		QuotaMode:              constants.QuotaModeCache,
+		SuspiciousPositionMode: constants.SuspiciousModeAnnotate,
+		RequestBindings:        []configs.RequestBinding{{Queue: "portals-requests"}},
+	}
+	deadLetterService, err := deadletters.New(config, mockdeadletters.New())
+	if err != nil {
+		t.Fatalf("cannot build dead letter service: %v", err)
+	}
+	boundsService, err := bounds.New(references.MapBounds{})
+	if err != nil {
+		t.Fatalf("cannot build bounds service: %v", err)
+	}
+	labelService, err := labels.New(references.Labels{})
+	if err != nil {
+		t.Fatalf("cannot build label service: %v", err)
+	}
+	confidenceService := confidences.New(24*time.Hour, boundsService)
+	snapshotService := snapshots.New(mocksnapshots.New())
+
+	broker := brokers.New()
+	portalService, err := portals.New(broker, config, refs.Servers, refs.Dimensions, refs.Areas,
+		refs.SubAreas, refs.Transports, deadLetterService, confidenceService, snapshotService, boundsService)
+	if err != nil {
+		t.Fatalf("cannot build portal service: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	return New(out, config, broker, portalService, refs.Servers, refs.Dimensions, refs.Areas, refs.SubAreas,
+		refs.Transports, deadLetterService, nil, confidenceService, snapshotService, nil, nil, boundsService,
+		labelService, nil, serverRepo, dimensionRepo, areaRepo, subAreaRepo, transportRepo), out
+}
+
+func TestIsCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected bool
+	}{
+		{name: "no argument"},
+		{name: "known command", args: []string{fetchCommand, "1"}, expected: true},
+		{name: "unknown command", args: []string{"--verbose"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if IsCommand(test.args) != test.expected {
+				t.Errorf("expected %v for %v", test.expected, test.args)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	records := filepath.Join(t.TempDir(), "records.jsonl")
+	if err := os.WriteFile(records, []byte(replayRecord+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedErr      error
+		expectedOutputs  []string
+		unexpectedOutput string
+	}{
+		{name: "unknown command", args: []string{"unknown"}, expectedErr: errUnknownCommand},
+		{name: "fetch without server", args: []string{fetchCommand}, expectedErr: errBadArguments},
+		{name: "fetch unknown language", args: []string{fetchCommand, "-lang", "xx", "1"},
+			expectedErr: errBadArguments},
+		{name: "fetch", args: []string{fetchCommand, "1"},
+			expectedOutputs: []string{`"serverId": "1"`, `"dimensionId": "enu"`, `"confidence"`, `"labels"`}},
+		{name: "fetch dimension", args: []string{fetchCommand, "-lang", "fr", "1", "enu"},
+			expectedOutputs: []string{`"dimensionId": "enu"`}},
+		{name: "check mappings", args: []string{checkMappingsCommand},
+			expectedOutputs: []string{"servers (2 unmapped)", "  - orukam", "  - draconiros",
+				"dimensions (1 unmapped)", "  - xelorium", "areas (0 unmapped)", "transportTypes (7 unmapped)"},
+			unexpectedOutput: "  - agride"},
+		{name: "check mappings with argument", args: []string{checkMappingsCommand, "1"},
+			expectedErr: errBadArguments},
+		{name: "sync reference", args: []string{syncReferenceCommand},
+			expectedOutputs: []string{"1 servers, 1 dimensions, 0 areas, 0 sub areas and 7 transport types synchronized",
+				"servers (1 skipped, already used as internal IDs)", "  - orukam"},
+			unexpectedOutput: "  - draconiros"},
+		{name: "sync reference with argument", args: []string{syncReferenceCommand, "1"},
+			expectedErr: errBadArguments},
+		{name: "replay", args: []string{replayCommand, records},
+			expectedOutputs: []string{`"correlationId":"replayed"`, `"replyTo":"requester"`,
+				`"type":"PORTAL_POSITION_ANSWER"`, `"serverId":"1"`}},
+		{name: "replay without file", args: []string{replayCommand}, expectedErr: errBadArguments},
+		{name: "replay missing file", args: []string{replayCommand, filepath.Join(t.TempDir(), "missing")},
+			expectedErr: os.ErrNotExist},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			command, out := newTestCommand(t)
+			err := command.Execute(context.Background(), test.args)
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if test.expectedErr != nil {
+				return
+			}
+
+			output := out.String()
+			for _, expected := range test.expectedOutputs {
+				if !strings.Contains(output, expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, output)
+				}
+			}
+			if test.unexpectedOutput != "" && strings.Contains(output, test.unexpectedOutput) {
+				t.Errorf("expected output not to contain %q, got:\n%s", test.unexpectedOutput, output)
+			}
+		})
+	}
+}
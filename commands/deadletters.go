@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// listDeadLetters prints every dead letter as JSON, with its decoded message.
+func (command *Impl) listDeadLetters(_ context.Context, args []string) error {
+	if len(args) != 0 {
+		return command.usage(errBadArguments)
+	}
+
+	deadLetters := command.deadLetterService.GetDeadLetters()
+	result := make([]deadLetter, 0, len(deadLetters))
+	for _, entity := range deadLetters {
+		var message amqp.RabbitMQMessage
+		if err := proto.Unmarshal(entity.Payload, &message); err != nil {
+			return err
+		}
+
+		data, err := protojson.Marshal(&message)
+		if err != nil {
+			return err
+		}
+
+		result = append(result, deadLetter{
+			ID:            entity.ID,
+			CorrelationID: entity.CorrelationID,
+			ReplyTo:       entity.ReplyTo,
+			RoutingKey:    entity.RoutingKey,
+			Error:         entity.Error,
+			Attempts:      entity.Attempts,
+			Quarantined:   entity.Quarantined,
+			CreatedAt:     entity.CreatedAt,
+			UpdatedAt:     entity.UpdatedAt,
+			Message:       data,
+		})
+	}
+
+	encoder := json.NewEncoder(command.out)
+	encoder.SetIndent("", jsonIndent)
+	return encoder.Encode(result)
+}
+
+// requeue treats dead letters again through the portal consumer, once the cause
+// of their failure is fixed. Replies are sent to the original requesters.
+func (command *Impl) requeue(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return command.usage(errBadArguments)
+	}
+
+	ids := make([]uint, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 0)
+		if err != nil {
+			return command.usage(fmt.Errorf("%w: %s", errBadArguments, arg))
+		}
+		ids = append(ids, uint(id))
+	}
+
+	config, err := configs.Load()
+	if err != nil {
+		return err
+	}
+
+	if err = command.broker.Run(); err != nil {
+		return err
+	}
+
+	broker := &requeueBroker{MessageBroker: command.broker}
+	portalService, err := portals.New(broker, config, command.serverService, command.dimensionService,
+		command.areaService, command.subAreaService, command.transportService, command.deadLetterService,
+		command.confidenceService, command.snapshotService, command.boundsService)
+	if err != nil {
+		return err
+	}
+	portalService.Consume()
+
+	for _, id := range ids {
+		msgCtx, message, errRequeue := command.deadLetterService.Requeue(id)
+		if errRequeue != nil {
+			return errRequeue
+		}
+
+		msgCtx.Context = ctx
+		broker.consumer(msgCtx, message)
+		fmt.Fprintf(command.out, "Dead letter %d treated again\n", id)
+	}
+
+	fmt.Fprintf(command.out, "%d dead letter(s) remaining\n", len(command.deadLetterService.GetDeadLetters()))
+	return nil
+}
+
+func (broker *requeueBroker) Consume(queueName string, consumer amqp.MessageConsumer) {
+	log.Debug().Str(constants.LogQueue, queueName).Msgf("Requeuing dead letters instead of consuming queue")
+	// Messages go through the first queue only.
+	if broker.consumer == nil {
+		broker.consumer = consumer
+	}
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	langFlag = "lang"
+)
+
+func (command *Impl) fetch(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet(fetchCommand, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	lang := flags.String(langFlag, amqp.Language_EN.String(), "language of the labels")
+	if err := flags.Parse(args); err != nil {
+		return command.usage(fmt.Errorf("%w: %w", errBadArguments, err))
+	}
+
+	language, found := amqp.Language_value[strings.ToUpper(*lang)]
+	if !found {
+		return command.usage(fmt.Errorf("%w: %s", errBadArguments, *lang))
+	}
+
+	params := flags.Args()
+	if len(params) < 1 || len(params) > 2 {
+		return command.usage(errBadArguments)
+	}
+
+	serverID := params[0]
+	var dimensionID string
+	if len(params) == 2 {
+		dimensionID = params[1]
+	}
+
+	portals, err := command.portalService.GetPortals(ctx, serverID, dimensionID)
+	if err != nil {
+		return err
+	}
+
+	return command.printPositions(portals, amqp.Language(language))
+}
+
+// printPositions prints positions as JSON, each one along with its confidence and its labels.
+func (command *Impl) printPositions(positions []*amqp.PortalPositionAnswer_PortalPosition,
+	language amqp.Language) error {
+	result := make([]map[string]any, 0, len(positions))
+	for _, position := range positions {
+		data, err := protojson.Marshal(position)
+		if err != nil {
+			return err
+		}
+
+		var fields map[string]any
+		if err = json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		fields[confidenceField] = command.confidenceService.Score(position)
+		fields[labelsField] = command.labelService.Localize(position, language)
+		result = append(result, fields)
+	}
+
+	encoder := json.NewEncoder(command.out)
+	encoder.SetIndent("", jsonIndent)
+	return encoder.Encode(result)
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+)
+
+// leaderboard prints the best contributors as JSON, for every server or only one.
+func (command *Impl) leaderboard(_ context.Context, args []string) error {
+	if len(args) > 2 {
+		return command.usage(errBadArguments)
+	}
+
+	var serverID, periodName string
+	if len(args) > 0 {
+		serverID = args[0]
+	}
+	if len(args) > 1 {
+		periodName = args[1]
+	}
+
+	period, err := constants.ParsePeriod(periodName)
+	if err != nil {
+		return command.usage(err)
+	}
+
+	contributions, err := command.snapshotService.GetLeaderboard(serverID, period)
+	if err != nil {
+		return err
+	}
+
+	result := make([]contribution, 0, len(contributions))
+	for _, entity := range contributions {
+		result = append(result, contribution{
+			Reporter:      entity.Reporter,
+			Contributions: entity.Contributions,
+			LastReportAt:  entity.LastReportAt,
+		})
+	}
+
+	encoder := json.NewEncoder(command.out)
+	encoder.SetIndent("", jsonIndent)
+	return encoder.Encode(result)
+}
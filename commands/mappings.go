@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+	"github.com/rs/zerolog/log"
+)
+
+func (command *Impl) checkMappings(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return command.usage(errBadArguments)
+	}
+
+	unmapped, err := command.getUnmappedCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	command.printIDs("servers", unmapped.servers)
+	command.printIDs("dimensions", unmapped.dimensions)
+	command.printIDs("areas", unmapped.areas)
+	command.printIDs("subAreas", unmapped.subAreas)
+	command.printIDs("transportTypes", unmapped.transportTypes)
+	return nil
+}
+
+func (command *Impl) printIDs(kind string, ids []string) {
+	fmt.Fprintf(command.out, "%s (%d unmapped)\n", kind, len(ids))
+	for _, id := range ids {
+		fmt.Fprintf(command.out, "  - %s\n", id)
+	}
+}
+
+// getUnmappedCatalog retrieves the dofus-portals catalog and keeps only IDs
+// that do not match any internal reference. Areas and sub areas are not exposed
+// as a catalog upstream, so they are collected from the current portal positions.
+func (command *Impl) getUnmappedCatalog(ctx context.Context) (catalog, error) {
+	upstream, err := command.getDofusPortalsCatalog(ctx)
+	if err != nil {
+		return catalog{}, err
+	}
+
+	return catalog{
+		servers: filter(upstream.servers, func(id string) bool {
+			_, found := command.serverService.FindServerByDofusPortalsID(id)
+			return found
+		}),
+		dimensions: filter(upstream.dimensions, func(id string) bool {
+			_, found := command.dimensionService.FindDimensionByDofusPortalsID(id)
+			return found
+		}),
+		areas: filter(upstream.areas, func(id string) bool {
+			_, found := command.areaService.FindAreaByDofusPortalsID(id)
+			return found
+		}),
+		subAreas: filter(upstream.subAreas, func(id string) bool {
+			_, found := command.subAreaService.FindSubAreaByDofusPortalsID(id)
+			return found
+		}),
+		transportTypes: filter(upstream.transportTypes, func(id string) bool {
+			_, found := command.transportService.FindTransportTypeByDofusPortalsID(id)
+			return found
+		}),
+	}, nil
+}
+
+func (command *Impl) getDofusPortalsCatalog(ctx context.Context) (catalog, error) {
+	dofusServers, err := command.portalService.GetDofusPortalsServers(ctx)
+	if err != nil {
+		return catalog{}, err
+	}
+
+	dofusDimensions, err := command.portalService.GetDofusPortalsDimensions(ctx)
+	if err != nil {
+		return catalog{}, err
+	}
+
+	servers := make([]string, 0, len(dofusServers))
+	areas := make([]string, 0)
+	subAreas := make([]string, 0)
+	transportTypes := []string{
+		string(dofusportals.Brigandin), string(dofusportals.CharAVoile),
+		string(dofusportals.Diligence), string(dofusportals.Foreuse),
+		string(dofusportals.Frigostien), string(dofusportals.Scaeroplane),
+		string(dofusportals.Skis), string(dofusportals.Zaap),
+	}
+	for _, server := range dofusServers {
+		servers = append(servers, server.Id)
+		dofusPortals, errGet := command.portalService.GetDofusPortalsPortals(ctx, server.Id)
+		if errGet != nil {
+			log.Warn().Err(errGet).Str(constants.LogServerID, server.Id).
+				Msgf("Cannot retrieve portals, areas and sub areas may be incomplete")
+			continue
+		}
+
+		for _, portal := range dofusPortals {
+			if portal.Position == nil {
+				continue
+			}
+
+			for _, transport := range []*dofusportals.Transport{
+				portal.Position.Transport, portal.Position.ConditionalTransport} {
+				if transport != nil {
+					areas = append(areas, transport.Area)
+					subAreas = append(subAreas, transport.SubArea)
+					transportTypes = append(transportTypes, string(transport.Type))
+				}
+			}
+		}
+	}
+
+	dimensions := make([]string, 0, len(dofusDimensions))
+	for _, dimension := range dofusDimensions {
+		dimensions = append(dimensions, dimension.Id)
+	}
+
+	return catalog{
+		servers:        unique(servers),
+		dimensions:     unique(dimensions),
+		areas:          unique(areas),
+		subAreas:       unique(subAreas),
+		transportTypes: unique(transportTypes),
+	}, nil
+}
+
+func filter(ids []string, isMapped func(id string) bool) []string {
+	result := make([]string, 0)
+	for _, id := range ids {
+		if !isMapped(id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+func unique(ids []string) []string {
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// exportReferences writes the reference data held in database to a snapshot file,
+// as YAML if its extension is .yaml or .yml, as JSON otherwise.
+func (command *Impl) exportReferences(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return command.usage(errBadArguments)
+	}
+
+	snapshot, err := command.referenceService.Export(args[0])
+	if err != nil {
+		return err
+	}
+
+	command.printReferences("exported to "+args[0], snapshot)
+	return nil
+}
+
+// importReferences saves the reference data of a snapshot file into database,
+// overwriting the entries having the same IDs.
+func (command *Impl) importReferences(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return command.usage(errBadArguments)
+	}
+
+	snapshot, err := command.referenceService.Import(args[0])
+	if err != nil {
+		return err
+	}
+
+	command.printReferences("imported from "+args[0], snapshot)
+	return nil
+}
+
+func (command *Impl) printReferences(action string, snapshot entities.ReferenceSnapshot) {
+	fmt.Fprintf(command.out, "%d servers, %d dimensions, %d areas, %d sub areas, %d transport types, "+
+		"%d map bounds and %d labels %s\n", len(snapshot.Servers), len(snapshot.Dimensions),
+		len(snapshot.Areas), len(snapshot.SubAreas), len(snapshot.TransportTypes),
+		len(snapshot.MapBounds), len(snapshot.Labels), action)
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// replay reads a JSON lines file, each line being a record of an AMQP message,
+// and runs them through the portal consumer. Replies are written as records
+// in the same format. W3C trace context found in record headers is propagated.
+// Neither dead letters nor snapshots are recorded while replaying.
+func (command *Impl) replay(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return command.usage(errBadArguments)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	broker := &replayBroker{out: command.out}
+	portalService, err := portals.New(broker, command.config, command.serverService, command.dimensionService,
+		command.areaService, command.subAreaService, command.transportService, replayDeadLetters{},
+		command.confidenceService, replaySnapshots{}, command.boundsService)
+	if err != nil {
+		return err
+	}
+	portalService.Consume()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxRecordSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var data record
+		if err = json.Unmarshal(line, &data); err != nil {
+			return err
+		}
+
+		var message amqp.RabbitMQMessage
+		if err = protojson.Unmarshal(data.Message, &message); err != nil {
+			return err
+		}
+
+		broker.consumer(amqp.Context{
+			Context:       insights.ExtractContext(ctx, data.Headers),
+			CorrelationID: data.CorrelationID,
+			ReplyTo:       data.ReplyTo,
+			Timestamp:     time.Now(),
+		}, &message)
+	}
+
+	return scanner.Err()
+}
+
+func (broker *replayBroker) Run() error {
+	return nil
+}
+
+func (broker *replayBroker) Emit(_ *amqp.RabbitMQMessage, _ amqp.Exchange, _, _ string) error {
+	return nil
+}
+
+func (broker *replayBroker) Request(_ *amqp.RabbitMQMessage, _ amqp.Exchange, _, _, _ string) error {
+	return nil
+}
+
+func (broker *replayBroker) Reply(msg *amqp.RabbitMQMessage, correlationID, replyTo string) error {
+	message, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(record{
+		CorrelationID: correlationID,
+		ReplyTo:       replyTo,
+		Message:       message,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = broker.out.Write(append(data, '\n'))
+	return err
+}
+
+func (broker *replayBroker) Consume(queueName string, consumer amqp.MessageConsumer) {
+	log.Debug().Str(constants.LogQueue, queueName).Msgf("Replaying messages instead of consuming queue")
+	// Messages go through the first queue only.
+	if broker.consumer == nil {
+		broker.consumer = consumer
+	}
+}
+
+func (broker *replayBroker) IsConnected() bool {
+	return true
+}
+
+func (broker *replayBroker) Shutdown() {}
+
+func (replayDeadLetters) Record(_ amqp.Context, _ *amqp.RabbitMQMessage, _ error, _ bool) {}
+
+func (replayDeadLetters) Resolve(_ string) {}
+
+func (replayDeadLetters) IsQuarantined(_ string) bool {
+	return false
+}
+
+func (replayDeadLetters) GetDeadLetters() []entities.DeadLetter {
+	return nil
+}
+
+func (replayDeadLetters) Requeue(_ uint) (amqp.Context, *amqp.RabbitMQMessage, error) {
+	return amqp.Context{}, nil, errNotRecorded
+}
+
+func (replayDeadLetters) Reload() error {
+	return nil
+}
+
+func (replayDeadLetters) Start() {}
+
+func (replayDeadLetters) Stop() {}
+
+func (replaySnapshots) Record(_ context.Context, _ []*amqp.PortalPositionAnswer_PortalPosition) {}
+
+func (replaySnapshots) GetLeaderboard(_ string, _ constants.Period) ([]entities.Contribution, error) {
+	return nil, nil
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/services/reports"
+)
+
+const (
+	canopyFlag = "canopy"
+	gameFlag   = "game"
+)
+
+// report validates a portal position on behalf of an operator, refused afterwards
+// since no source accepts writes.
+func (command *Impl) report(_ context.Context, args []string) error {
+	flags := flag.NewFlagSet(reportCommand, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	isInCanopy := flags.Bool(canopyFlag, false, "position in canopy")
+	gameName := flags.String(gameFlag, amqp.Game_ANY_GAME.String(), "game of the position")
+	if err := flags.Parse(args); err != nil {
+		return command.usage(fmt.Errorf("%w: %w", errBadArguments, err))
+	}
+
+	game, found := amqp.Game_value[strings.ToUpper(*gameName)]
+	if !found {
+		return command.usage(fmt.Errorf("%w: %s", errBadArguments, *gameName))
+	}
+
+	params := flags.Args()
+	if len(params) != 4 {
+		return command.usage(errBadArguments)
+	}
+
+	coordinates := make([]int64, 0, 2)
+	for _, param := range params[2:] {
+		coordinate, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return command.usage(fmt.Errorf("%w: %s", errBadArguments, param))
+		}
+		coordinates = append(coordinates, coordinate)
+	}
+
+	err := command.reportService.Report(reports.Report{
+		ServerID:    params[0],
+		DimensionID: params[1],
+		X:           coordinates[0],
+		Y:           coordinates[1],
+		IsInCanopy:  *isInCanopy,
+		Game:        amqp.Game(game),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(command.out, "Portal reported\n")
+	return nil
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// statistics prints the portal statistics of a server as JSON, per dimension or for only one.
+func (command *Impl) statistics(_ context.Context, args []string) error {
+	if len(args) < 1 || len(args) > 3 {
+		return command.usage(errBadArguments)
+	}
+
+	serverID := args[0]
+	var periodName, dimensionID string
+	if len(args) > 1 {
+		periodName = args[1]
+	}
+	if len(args) > 2 {
+		dimensionID = args[2]
+	}
+
+	period, err := constants.ParsePeriod(periodName)
+	if err != nil {
+		return command.usage(err)
+	}
+
+	dimensions, err := command.statisticService.GetStatistics(serverID, dimensionID, period)
+	if err != nil {
+		return err
+	}
+
+	result := make([]dimensionStatistics, 0, len(dimensions))
+	for _, entity := range dimensions {
+		result = append(result, dimensionStatistics{
+			ServerID:               entity.ServerID,
+			DimensionID:            entity.DimensionID,
+			Positions:              entity.Positions,
+			Areas:                  mapFrequencies(entity.Areas),
+			SubAreas:               mapFrequencies(entity.SubAreas),
+			AverageLifetimeSeconds: entity.AverageLifetime.Seconds(),
+			AverageUsesPerDay:      entity.AverageUsesPerDay,
+			CanopyFrequency:        entity.CanopyFrequency,
+		})
+	}
+
+	encoder := json.NewEncoder(command.out)
+	encoder.SetIndent("", jsonIndent)
+	return encoder.Encode(result)
+}
+
+func mapFrequencies(frequencies []entities.Frequency) []frequency {
+	result := make([]frequency, 0, len(frequencies))
+	for _, entity := range frequencies {
+		result = append(result, frequency{ID: entity.ID, Positions: entity.Positions})
+	}
+	return result
+}
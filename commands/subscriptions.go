@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+const (
+	minUsesFlag   = "min-uses"
+	transportFlag = "transport"
+)
+
+// subscribe registers a guild channel to the portal changes of a server,
+// optionally filtered by dimension, remaining uses and nearest transport type.
+func (command *Impl) subscribe(_ context.Context, args []string) error {
+	flags := flag.NewFlagSet(subscribeCommand, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	minUses := flags.Int64(minUsesFlag, 0, "minimum remaining uses")
+	transportTypeID := flags.String(transportFlag, "", "nearest transport type")
+	if err := flags.Parse(args); err != nil {
+		return command.usage(fmt.Errorf("%w: %w", errBadArguments, err))
+	}
+
+	params := flags.Args()
+	if len(params) < 3 || len(params) > 4 {
+		return command.usage(errBadArguments)
+	}
+
+	if _, found := command.serverService.GetServer(params[2]); !found {
+		return fmt.Errorf("%w: %s", errUnknownServer, params[2])
+	}
+
+	subscription := entities.Subscription{
+		GuildID:          params[0],
+		ChannelID:        params[1],
+		ServerID:         params[2],
+		MinRemainingUses: *minUses,
+		TransportTypeID:  *transportTypeID,
+	}
+	if len(params) == 4 {
+		if _, found := command.dimensionService.GetDimension(params[3]); !found {
+			return fmt.Errorf("%w: %s", errUnknownDimension, params[3])
+		}
+		subscription.DimensionID = params[3]
+	}
+
+	subscription, err := command.subscriptionService.Subscribe(subscription)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(command.out, "Subscription %d created\n", subscription.ID)
+	return nil
+}
+
+func (command *Impl) unsubscribe(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return command.usage(errBadArguments)
+	}
+
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return command.usage(fmt.Errorf("%w: %s", errBadArguments, args[0]))
+	}
+
+	if err = command.subscriptionService.Unsubscribe(uint(id)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(command.out, "Subscription %d removed\n", id)
+	return nil
+}
+
+// listSubscriptions prints subscriptions as JSON, for every guild or only one.
+func (command *Impl) listSubscriptions(_ context.Context, args []string) error {
+	if len(args) > 1 {
+		return command.usage(errBadArguments)
+	}
+
+	result := make([]subscription, 0)
+	for _, entity := range command.subscriptionService.GetSubscriptions() {
+		if len(args) == 0 || entity.GuildID == args[0] {
+			result = append(result, subscription{
+				ID:               entity.ID,
+				GuildID:          entity.GuildID,
+				ChannelID:        entity.ChannelID,
+				ServerID:         entity.ServerID,
+				DimensionID:      entity.DimensionID,
+				MinRemainingUses: entity.MinRemainingUses,
+				TransportTypeID:  entity.TransportTypeID,
+				CreatedAt:        entity.CreatedAt,
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(command.out)
+	encoder.SetIndent("", jsonIndent)
+	return encoder.Encode(result)
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// syncReference inserts every unmapped dofus-portals ID in database, using it
+// as internal ID as well; this is the same fallback than the one applied when
+// mapping portals. IDs already used as internal IDs are skipped and reported,
+// since saving them would overwrite the mapping of another dofus-portals ID.
+func (command *Impl) syncReference(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return command.usage(errBadArguments)
+	}
+
+	unmapped, err := command.getUnmappedCatalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	internal, err := command.getInternalCatalog()
+	if err != nil {
+		return err
+	}
+
+	skipped, err := command.insertCatalog(unmapped, internal)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(command.out, "%d servers, %d dimensions, %d areas, %d sub areas and %d transport types synchronized\n",
+		len(unmapped.servers)-len(skipped.servers), len(unmapped.dimensions)-len(skipped.dimensions),
+		len(unmapped.areas)-len(skipped.areas), len(unmapped.subAreas)-len(skipped.subAreas),
+		len(unmapped.transportTypes)-len(skipped.transportTypes))
+	command.printSkippedIDs("servers", skipped.servers)
+	command.printSkippedIDs("dimensions", skipped.dimensions)
+	command.printSkippedIDs("areas", skipped.areas)
+	command.printSkippedIDs("subAreas", skipped.subAreas)
+	command.printSkippedIDs("transportTypes", skipped.transportTypes)
+	return nil
+}
+
+func (command *Impl) printSkippedIDs(kind string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	fmt.Fprintf(command.out, "%s (%d skipped, already used as internal IDs)\n", kind, len(ids))
+	for _, id := range ids {
+		fmt.Fprintf(command.out, "  - %s\n", id)
+	}
+}
+
+// getInternalCatalog reads the internal IDs straight from the database.
+func (command *Impl) getInternalCatalog() (catalog, error) {
+	servers, err := command.serverRepo.GetServers()
+	if err != nil {
+		return catalog{}, err
+	}
+
+	dimensions, err := command.dimensionRepo.GetDimensions()
+	if err != nil {
+		return catalog{}, err
+	}
+
+	areas, err := command.areaRepo.GetAreas()
+	if err != nil {
+		return catalog{}, err
+	}
+
+	subAreas, err := command.subAreaRepo.GetSubAreas()
+	if err != nil {
+		return catalog{}, err
+	}
+
+	transportTypes, err := command.transportRepo.GetTransportTypes()
+	if err != nil {
+		return catalog{}, err
+	}
+
+	return catalog{
+		servers:        getIDs(servers, func(server entities.Server) string { return server.ID }),
+		dimensions:     getIDs(dimensions, func(dimension entities.Dimension) string { return dimension.ID }),
+		areas:          getIDs(areas, func(area entities.Area) string { return area.ID }),
+		subAreas:       getIDs(subAreas, func(subArea entities.SubArea) string { return subArea.ID }),
+		transportTypes: getIDs(transportTypes, func(transportType entities.TransportType) string { return transportType.ID }),
+	}, nil
+}
+
+// insertCatalog saves the unmapped IDs that are not internal IDs yet, and returns the skipped ones.
+func (command *Impl) insertCatalog(unmapped, internal catalog) (catalog, error) {
+	var skipped catalog
+	var err error
+	skipped.servers, err = insertIDs(unmapped.servers, internal.servers, func(id string) error {
+		return command.serverRepo.SaveServer(entities.Server{ID: id, DofusPortalsID: id})
+	})
+	if err != nil {
+		return catalog{}, err
+	}
+
+	skipped.dimensions, err = insertIDs(unmapped.dimensions, internal.dimensions, func(id string) error {
+		return command.dimensionRepo.SaveDimension(entities.Dimension{ID: id, DofusPortalsID: id})
+	})
+	if err != nil {
+		return catalog{}, err
+	}
+
+	skipped.areas, err = insertIDs(unmapped.areas, internal.areas, func(id string) error {
+		return command.areaRepo.SaveArea(entities.Area{ID: id, DofusPortalsID: id})
+	})
+	if err != nil {
+		return catalog{}, err
+	}
+
+	skipped.subAreas, err = insertIDs(unmapped.subAreas, internal.subAreas, func(id string) error {
+		return command.subAreaRepo.SaveSubArea(entities.SubArea{ID: id, DofusPortalsID: id})
+	})
+	if err != nil {
+		return catalog{}, err
+	}
+
+	skipped.transportTypes, err = insertIDs(unmapped.transportTypes, internal.transportTypes, func(id string) error {
+		return command.transportRepo.SaveTransportType(entities.TransportType{ID: id, DofusPortalsID: id})
+	})
+	if err != nil {
+		return catalog{}, err
+	}
+
+	return skipped, nil
+}
+
+func insertIDs(ids, internalIDs []string, save func(id string) error) ([]string, error) {
+	skipped := make([]string, 0)
+	for _, id := range ids {
+		if slices.Contains(internalIDs, id) {
+			skipped = append(skipped, id)
+			continue
+		}
+
+		if err := save(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return skipped, nil
+}
+
+func getIDs[T any](references []T, getID func(reference T) string) []string {
+	ids := make([]string, 0, len(references))
+	for _, reference := range references {
+		ids = append(ids, getID(reference))
+	}
+
+	return ids
+}
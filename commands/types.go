@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	areaRepo "github.com/kaellybot/kaelly-portals/repositories/areas"
+	dimensionRepo "github.com/kaellybot/kaelly-portals/repositories/dimensions"
+	serverRepo "github.com/kaellybot/kaelly-portals/repositories/servers"
+	subAreaRepo "github.com/kaellybot/kaelly-portals/repositories/subareas"
+	transportRepo "github.com/kaellybot/kaelly-portals/repositories/transports"
+	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/labels"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/references"
+	"github.com/kaellybot/kaelly-portals/services/reports"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
+	"github.com/kaellybot/kaelly-portals/services/statistics"
+	"github.com/kaellybot/kaelly-portals/services/subareas"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+const (
+	fetchCommand         = "fetch"
+	checkMappingsCommand = "check-mappings"
+	syncReferenceCommand = "sync-reference"
+	replayCommand        = "replay"
+	deadLettersCommand   = "dead-letters"
+	requeueCommand       = "requeue"
+	subscribeCommand     = "subscribe"
+	unsubscribeCommand   = "unsubscribe"
+	subscriptionsCommand = "subscriptions"
+	leaderboardCommand   = "leaderboard"
+	statisticsCommand    = "statistics"
+	reportCommand        = "report"
+	exportRefsCommand    = "export-references"
+	importRefsCommand    = "import-references"
+
+	jsonIndent      = "  "
+	confidenceField = "confidence"
+	labelsField     = "labels"
+	maxRecordSize   = 1024 * 1024
+	usage           = `Usage: %s <command> [arguments]
+
+Commands:
+  fetch [-lang language] <server> [dimension]
+                              print mapped portals as JSON, with labels in fr, en, es or de
+  check-mappings              list dofus-portals IDs without internal mapping
+  sync-reference              insert unmapped dofus-portals IDs into the database, skipping used IDs
+  replay <file>               run recorded AMQP messages through the portal consumer
+  dead-letters                list failed and quarantined requests as JSON
+  requeue <id>...             treat dead letters again and reply to their requesters
+  subscribe [-min-uses n] [-transport type] <guild> <channel> <server> [dimension]
+                              alert a guild channel when a portal of the server changes
+  unsubscribe <id>            remove a subscription
+  subscriptions [guild]       list subscriptions as JSON
+  leaderboard [server] [period]
+                              list best contributors over day, week, month or all (default)
+  statistics <server> [period] [dimension]
+                              print portal statistics per dimension as JSON, over a period
+  report [-canopy] [-game game] <server> <dimension> <x> <y>
+                              validate a portal position, refused since no source accepts writes
+  export-references <file>    write reference data to a JSON, or YAML with .yaml or .yml extension
+  import-references <file>    save reference data of a JSON or YAML file into the database
+`
+)
+
+var (
+	errUnknownCommand   = errors.New("unknown command")
+	errUnknownServer    = errors.New("unknown server")
+	errUnknownDimension = errors.New("unknown dimension")
+	errBadArguments     = errors.New("bad number of arguments")
+	errNotRecorded      = errors.New("dead letters are not recorded while replaying")
+)
+
+type Command interface {
+	Execute(ctx context.Context, args []string) error
+}
+
+type Impl struct {
+	out                 io.Writer
+	config              configs.Config
+	broker              amqp.MessageBroker
+	portalService       portals.Service
+	serverService       servers.Service
+	dimensionService    dimensions.Service
+	areaService         areas.Service
+	subAreaService      subareas.Service
+	transportService    transports.Service
+	deadLetterService   deadletters.Service
+	subscriptionService subscriptions.Service
+	confidenceService   confidences.Service
+	snapshotService     snapshots.Service
+	statisticService    statistics.Service
+	reportService       reports.Service
+	boundsService       bounds.Service
+	labelService        labels.Service
+	referenceService    references.Service
+	serverRepo          serverRepo.Repository
+	dimensionRepo       dimensionRepo.Repository
+	areaRepo            areaRepo.Repository
+	subAreaRepo         subAreaRepo.Repository
+	transportRepo       transportRepo.Repository
+}
+
+type catalog struct {
+	servers        []string
+	dimensions     []string
+	areas          []string
+	subAreas       []string
+	transportTypes []string
+}
+
+type record struct {
+	CorrelationID string            `json:"correlationId"`
+	ReplyTo       string            `json:"replyTo"`
+	Message       json.RawMessage   `json:"message"`
+	Headers       map[string]string `json:"headers,omitempty"`
+}
+
+type deadLetter struct {
+	ID            uint            `json:"id"`
+	CorrelationID string          `json:"correlationId"`
+	ReplyTo       string          `json:"replyTo"`
+	RoutingKey    string          `json:"routingKey"`
+	Error         string          `json:"error"`
+	Attempts      int             `json:"attempts"`
+	Quarantined   bool            `json:"quarantined"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+	Message       json.RawMessage `json:"message"`
+}
+
+type contribution struct {
+	Reporter      string    `json:"reporter"`
+	Contributions int64     `json:"contributions"`
+	LastReportAt  time.Time `json:"lastReportAt"`
+}
+
+type dimensionStatistics struct {
+	ServerID               string      `json:"serverId"`
+	DimensionID            string      `json:"dimensionId"`
+	Positions              int         `json:"positions"`
+	Areas                  []frequency `json:"areas"`
+	SubAreas               []frequency `json:"subAreas"`
+	AverageLifetimeSeconds float64     `json:"averageLifetimeSeconds"`
+	AverageUsesPerDay      float64     `json:"averageUsesPerDay"`
+	CanopyFrequency        float64     `json:"canopyFrequency"`
+}
+
+type frequency struct {
+	ID        string `json:"id"`
+	Positions int    `json:"positions"`
+}
+
+type subscription struct {
+	ID               uint      `json:"id"`
+	GuildID          string    `json:"guildId"`
+	ChannelID        string    `json:"channelId"`
+	ServerID         string    `json:"serverId"`
+	DimensionID      string    `json:"dimensionId,omitempty"`
+	MinRemainingUses int64     `json:"minRemainingUses,omitempty"`
+	TransportTypeID  string    `json:"transportTypeId,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// requeueBroker forwards replies to the real broker but captures the consumer
+// instead of consuming the request queue.
+type requeueBroker struct {
+	amqp.MessageBroker
+	consumer amqp.MessageConsumer
+}
+
+type replayBroker struct {
+	out      io.Writer
+	consumer amqp.MessageConsumer
+}
+
+// replayDeadLetters and replaySnapshots record nothing, so that replays leave
+// the dead letters and the snapshots of the consumer untouched.
+type replayDeadLetters struct{}
+
+type replaySnapshots struct{}
@@ -8,7 +8,9 @@ import (
 	"syscall"
 
 	"github.com/kaellybot/kaelly-portals/application"
+	"github.com/kaellybot/kaelly-portals/commands"
 	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -49,13 +51,12 @@ func initLog() {
 		return fmt.Sprintf("%s:%d", short, line)
 	}
 	log.Logger = log.With().Caller().Logger()
+}
 
-	logLevel, err := zerolog.ParseLevel(viper.GetString(constants.LogLevel))
-	if err != nil {
-		log.Warn().Err(err).Msgf("Log level not set, continue with %s...", constants.LogLevelFallback)
-	} else {
-		zerolog.SetGlobalLevel(logLevel)
-		log.Debug().Msgf("Logger level set to '%s'", logLevel)
+func reloadLog(runtime configs.Runtime) {
+	if zerolog.GlobalLevel() != runtime.LogLevel {
+		zerolog.SetGlobalLevel(runtime.LogLevel)
+		log.Info().Msgf("Logger level set to '%s'", runtime.LogLevel)
 	}
 }
 
@@ -77,16 +78,40 @@ func initMetrics() {
 }
 
 func main() {
-	app, err := application.New()
+	config, err := configs.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Shutting down after failing to validate configuration")
+	}
+
+	zerolog.SetGlobalLevel(config.LogLevel)
+	config.LogSummary()
+
+	args := os.Args[1:]
+	isCommand := commands.IsCommand(args)
+	if !isCommand && len(args) > 0 {
+		log.Warn().Strs(constants.LogArguments, args).Msgf("Unknown command, arguments ignored")
+	}
+	app, err := application.New(config, !isCommand)
 	if err != nil {
 		log.Fatal().Err(err).Msgf("Shutting down after failing to instantiate application")
 	}
 
+	if isCommand {
+		err = app.Execute(args)
+		app.Shutdown()
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Command failed")
+		}
+		return
+	}
+
 	err = app.Run()
 	if err != nil {
 		log.Fatal().Err(err).Msgf("Shutting down after failing to run application.")
 	}
 
+	configs.Watch(reloadLog, app.Reload)
+
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	log.Info().Msgf("%s v%s is now running. Press CTRL-C to exit.", constants.InternalName, constants.Version)
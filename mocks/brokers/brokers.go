@@ -0,0 +1,80 @@
+package brokers
+
+import (
+	amqp "github.com/kaellybot/kaelly-amqp"
+)
+
+func New() *Broker {
+	return &Broker{
+		replies:   make([]Reply, 0),
+		consumers: make(map[string]amqp.MessageConsumer),
+	}
+}
+
+// Replies returns every message replied so far.
+func (broker *Broker) Replies() []Reply {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	return append([]Reply{}, broker.replies...)
+}
+
+// Emissions returns every message emitted so far.
+func (broker *Broker) Emissions() []Emission {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	return append([]Emission{}, broker.emissions...)
+}
+
+// Deliver calls the consumer registered for the queue, if any.
+func (broker *Broker) Deliver(queueName string, ctx amqp.Context, message *amqp.RabbitMQMessage) bool {
+	broker.mutex.Lock()
+	consumer, found := broker.consumers[queueName]
+	broker.mutex.Unlock()
+	if found {
+		consumer(ctx, message)
+	}
+	return found
+}
+
+func (broker *Broker) Run() error {
+	return nil
+}
+
+func (broker *Broker) Emit(msg *amqp.RabbitMQMessage, exchange amqp.Exchange, routingKey, correlationID string) error {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.emissions = append(broker.emissions, Emission{
+		Message:       msg,
+		Exchange:      exchange,
+		RoutingKey:    routingKey,
+		CorrelationID: correlationID,
+	})
+	return nil
+}
+
+func (broker *Broker) Request(_ *amqp.RabbitMQMessage, _ amqp.Exchange, _, _, _ string) error {
+	return nil
+}
+
+func (broker *Broker) Reply(msg *amqp.RabbitMQMessage, correlationID, replyTo string) error {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.replies = append(broker.replies, Reply{
+		Message:       msg,
+		CorrelationID: correlationID,
+		ReplyTo:       replyTo,
+	})
+	return nil
+}
+
+func (broker *Broker) Consume(queueName string, consumer amqp.MessageConsumer) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.consumers[queueName] = consumer
+}
+
+func (broker *Broker) IsConnected() bool {
+	return true
+}
+
+func (broker *Broker) Shutdown() {}
@@ -0,0 +1,31 @@
+package brokers
+
+import (
+	"sync"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+)
+
+// Reply is a message replied through the fake broker.
+type Reply struct {
+	Message       *amqp.RabbitMQMessage
+	CorrelationID string
+	ReplyTo       string
+}
+
+// Emission is a message emitted through the fake broker.
+type Emission struct {
+	Message       *amqp.RabbitMQMessage
+	Exchange      amqp.Exchange
+	RoutingKey    string
+	CorrelationID string
+}
+
+// Broker is a fake amqp.MessageBroker keeping track of replies
+// and emissions, and of the registered consumers.
+type Broker struct {
+	mutex     sync.Mutex
+	replies   []Reply
+	emissions []Emission
+	consumers map[string]amqp.MessageConsumer
+}
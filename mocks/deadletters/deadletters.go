@@ -0,0 +1,68 @@
+package deadletters
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+func New(deadLetters ...entities.DeadLetter) *Repository {
+	repo := Repository{deadLetters: make(map[uint]entities.DeadLetter)}
+	for _, deadLetter := range deadLetters {
+		repo.lastID++
+		deadLetter.ID = repo.lastID
+		if deadLetter.UpdatedAt.IsZero() {
+			deadLetter.UpdatedAt = time.Now()
+		}
+		repo.deadLetters[deadLetter.ID] = deadLetter
+	}
+	return &repo
+}
+
+func (repo *Repository) GetDeadLetters() ([]entities.DeadLetter, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	deadLetters := make([]entities.DeadLetter, 0, len(repo.deadLetters))
+	for _, deadLetter := range repo.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].ID < deadLetters[j].ID
+	})
+	return deadLetters, nil
+}
+
+func (repo *Repository) SaveDeadLetter(deadLetter *entities.DeadLetter) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if deadLetter.ID == 0 {
+		repo.lastID++
+		deadLetter.ID = repo.lastID
+	}
+	deadLetter.UpdatedAt = time.Now()
+	repo.deadLetters[deadLetter.ID] = *deadLetter
+	return nil
+}
+
+func (repo *Repository) DeleteDeadLetter(id uint) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	delete(repo.deadLetters, id)
+	return nil
+}
+
+func (repo *Repository) DeleteDeadLettersBefore(date time.Time) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	for id, deadLetter := range repo.deadLetters {
+		if deadLetter.UpdatedAt.Before(date) {
+			delete(repo.deadLetters, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package deadletters
+
+import (
+	"sync"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// Repository is an in-memory dead letter repository.
+type Repository struct {
+	mutex       sync.Mutex
+	lastID      uint
+	deadLetters map[uint]entities.DeadLetter
+}
@@ -0,0 +1,148 @@
+package dofusportals
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+)
+
+// New starts a fake dofus-portals server accepting the provided token.
+// Caller is expected to defer call Close().
+func New(token string) *Server {
+	server := Server{
+		token:      token,
+		servers:    make([]dofusportals.Server, 0),
+		dimensions: make([]dofusportals.Dimension, 0),
+		portals:    make(map[string][]dofusportals.Portal),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /external/v1/servers", server.getServers)
+	mux.HandleFunc("GET /external/v1/dimensions", server.getDimensions)
+	mux.HandleFunc("GET /external/v1/servers/{serverId}/portals", server.getPortals)
+	mux.HandleFunc("GET /external/v1/servers/{serverId}/portals/{dimensionId}", server.getPortal)
+	server.Server = httptest.NewServer(server.middleware(mux))
+	return &server
+}
+
+// Script changes the behaviour applied to the next requests.
+func (server *Server) Script(behaviour Behaviour) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.behaviour = behaviour
+}
+
+func (server *Server) SetServers(servers ...dofusportals.Server) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.servers = servers
+}
+
+func (server *Server) SetDimensions(dimensions ...dofusportals.Dimension) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.dimensions = dimensions
+}
+
+// SetPortals replaces the portals of the server they belong to.
+func (server *Server) SetPortals(portals ...dofusportals.Portal) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	for _, portal := range portals {
+		delete(server.portals, portal.Server)
+	}
+	for _, portal := range portals {
+		server.portals[portal.Server] = append(server.portals[portal.Server], portal)
+	}
+}
+
+// Requests returns the number of requests received so far.
+func (server *Server) Requests() int64 {
+	return server.requests.Load()
+}
+
+func (server *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+		server.mutex.RLock()
+		behaviour := server.behaviour
+		server.mutex.RUnlock()
+
+		if behaviour.Latency > 0 {
+			select {
+			case <-time.After(behaviour.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		if r.Header.Get(tokenHeader) != server.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case behaviour.IntendedError != "":
+			writeJSON(w, http.StatusBadRequest, dofusportals.IntendedError{Error: behaviour.IntendedError})
+		case behaviour.StatusCode != 0:
+			w.WriteHeader(behaviour.StatusCode)
+		case behaviour.Malformed:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(malformed))
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (server *Server) getServers(w http.ResponseWriter, _ *http.Request) {
+	server.mutex.RLock()
+	defer server.mutex.RUnlock()
+	writeJSON(w, http.StatusOK, server.servers)
+}
+
+func (server *Server) getDimensions(w http.ResponseWriter, _ *http.Request) {
+	server.mutex.RLock()
+	defer server.mutex.RUnlock()
+	writeJSON(w, http.StatusOK, server.dimensions)
+}
+
+func (server *Server) getPortals(w http.ResponseWriter, r *http.Request) {
+	server.mutex.RLock()
+	defer server.mutex.RUnlock()
+	portals, found := server.portals[r.PathValue("serverId")]
+	if !found {
+		writeJSON(w, http.StatusBadRequest, dofusportals.IntendedError{Error: dofusportals.ServerNotFound})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, portals)
+}
+
+func (server *Server) getPortal(w http.ResponseWriter, r *http.Request) {
+	server.mutex.RLock()
+	defer server.mutex.RUnlock()
+	portals, found := server.portals[r.PathValue("serverId")]
+	if !found {
+		writeJSON(w, http.StatusBadRequest, dofusportals.IntendedError{Error: dofusportals.ServerNotFound})
+		return
+	}
+
+	for _, portal := range portals {
+		if portal.Dimension == r.PathValue("dimensionId") {
+			writeJSON(w, http.StatusOK, portal)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusBadRequest, dofusportals.IntendedError{Error: dofusportals.DimensionNotFound})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,148 @@
+package dofusportals
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+)
+
+const token = "token"
+
+func TestContract(t *testing.T) {
+	spec, err := dofusportals.GetSwagger()
+	if err != nil {
+		t.Fatalf("cannot load spec: %v", err)
+	}
+
+	server := New(token)
+	defer server.Close()
+	spec.Servers = nil
+	spec.AddServer(&openapi3.Server{URL: server.URL})
+	router, err := legacy.NewRouter(spec)
+	if err != nil {
+		t.Fatalf("cannot build router: %v", err)
+	}
+
+	remainingUses := float32(42)
+	server.SetServers(dofusportals.Server{Id: "agride", Active: true,
+		Community: dofusportals.Fr, Type: dofusportals.Multi})
+	server.SetDimensions(dofusportals.Dimension{Id: "enutrosor"})
+	server.SetPortals(dofusportals.Portal{Server: "agride", Dimension: "enutrosor",
+		RemainingUses: &remainingUses, Position: &dofusportals.Position{X: 5, Y: -18}})
+
+	tests := []struct {
+		name       string
+		path       string
+		behaviour  Behaviour
+		statusCode int
+	}{
+		{name: "servers", path: "/external/v1/servers", statusCode: http.StatusOK},
+		{name: "dimensions", path: "/external/v1/dimensions", statusCode: http.StatusOK},
+		{name: "portals", path: "/external/v1/servers/agride/portals", statusCode: http.StatusOK},
+		{name: "portal", path: "/external/v1/servers/agride/portals/enutrosor", statusCode: http.StatusOK},
+		{name: "unknown server", path: "/external/v1/servers/unknown/portals", statusCode: http.StatusBadRequest},
+		{name: "unknown dimension", path: "/external/v1/servers/agride/portals/unknown",
+			statusCode: http.StatusBadRequest},
+		{name: "intended error", path: "/external/v1/servers/agride/portals",
+			behaviour: Behaviour{IntendedError: dofusportals.TokenNotFound}, statusCode: http.StatusBadRequest},
+		{name: "unexpected error", path: "/external/v1/servers/agride/portals",
+			behaviour:  Behaviour{StatusCode: http.StatusInternalServerError},
+			statusCode: http.StatusInternalServerError},
+		{name: "latency", path: "/external/v1/dimensions",
+			behaviour: Behaviour{Latency: 10 * time.Millisecond}, statusCode: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server.Script(test.behaviour)
+			request, errReq := http.NewRequestWithContext(context.Background(), http.MethodGet,
+				server.URL+test.path, nil)
+			if errReq != nil {
+				t.Fatalf("cannot build request: %v", errReq)
+			}
+			request.Header.Set(tokenHeader, token)
+
+			response, errDo := http.DefaultClient.Do(request)
+			if errDo != nil {
+				t.Fatalf("cannot call fake server: %v", errDo)
+			}
+			defer response.Body.Close()
+			body, errRead := io.ReadAll(response.Body)
+			if errRead != nil {
+				t.Fatalf("cannot read body: %v", errRead)
+			}
+
+			if response.StatusCode != test.statusCode {
+				t.Fatalf("expected status %d, got %d", test.statusCode, response.StatusCode)
+			}
+
+			route, pathParams, errRoute := router.FindRoute(request)
+			if errRoute != nil {
+				t.Fatalf("cannot find route in spec: %v", errRoute)
+			}
+
+			errValidate := openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    request,
+					PathParams: pathParams,
+					Route:      route,
+					Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+				},
+				Status: response.StatusCode,
+				Header: response.Header,
+				Body:   io.NopCloser(strings.NewReader(string(body))),
+			})
+			if errValidate != nil {
+				t.Errorf("response does not match the contract: %v", errValidate)
+			}
+		})
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	server := New(token)
+	defer server.Close()
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+		server.URL+"/external/v1/servers", nil)
+	if err != nil {
+		t.Fatalf("cannot build request: %v", err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("cannot call fake server: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, response.StatusCode)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	server := New(token)
+	defer server.Close()
+	server.Script(Behaviour{Malformed: true})
+
+	client, err := dofusportals.NewClientWithResponses(server.URL,
+		dofusportals.WithRequestEditorFn(func(_ context.Context, req *http.Request) error {
+			req.Header.Set(tokenHeader, token)
+			return nil
+		}))
+	if err != nil {
+		t.Fatalf("cannot build client: %v", err)
+	}
+
+	if _, err = client.GetExternalV1ServersWithResponse(context.Background()); err == nil {
+		t.Errorf("expected malformed payload to fail decoding")
+	}
+}
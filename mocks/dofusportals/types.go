@@ -0,0 +1,40 @@
+package dofusportals
+
+import (
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+)
+
+const (
+	tokenHeader = "token"
+	malformed   = `{"server": [`
+)
+
+// Behaviour scripts the way the fake server answers the next requests.
+// The zero value answers normally.
+type Behaviour struct {
+	// Latency applied before answering.
+	Latency time.Duration
+	// IntendedError, if set, is answered with a 400 status code.
+	IntendedError dofusportals.IntendedErrorError
+	// StatusCode, if set, is answered without body.
+	StatusCode int
+	// Malformed answers a 200 status code with a payload that cannot be decoded.
+	Malformed bool
+}
+
+// Server is an in-process fake implementing the dofus-portals contract.
+type Server struct {
+	*httptest.Server
+	mutex      sync.RWMutex
+	token      string
+	requests   atomic.Int64
+	behaviour  Behaviour
+	servers    []dofusportals.Server
+	dimensions []dofusportals.Dimension
+	portals    map[string][]dofusportals.Portal
+}
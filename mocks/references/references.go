@@ -0,0 +1,28 @@
+package references
+
+import (
+	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/subareas"
+	"github.com/kaellybot/kaelly-portals/services/transports"
+)
+
+// New builds the reference services on top of in-memory repositories.
+// Since in-memory repositories never fail, neither does this function.
+func New(serverRepo Servers, dimensionRepo Dimensions, areaRepo Areas,
+	subAreaRepo SubAreas, transportTypeRepo TransportTypes) Services {
+	serverService, _ := servers.New(serverRepo)
+	dimensionService, _ := dimensions.New(dimensionRepo)
+	areaService, _ := areas.New(areaRepo)
+	subAreaService, _ := subareas.New(subAreaRepo)
+	transportService, _ := transports.New(transportTypeRepo)
+
+	return Services{
+		Servers:    serverService,
+		Dimensions: dimensionService,
+		Areas:      areaService,
+		SubAreas:   subAreaService,
+		Transports: transportService,
+	}
+}
@@ -0,0 +1,82 @@
+package references
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// Servers is an in-memory server repository.
+type Servers []entities.Server
+
+// Dimensions is an in-memory dimension repository.
+type Dimensions []entities.Dimension
+
+// Areas is an in-memory area repository.
+type Areas []entities.Area
+
+// SubAreas is an in-memory sub area repository.
+type SubAreas []entities.SubArea
+
+// TransportTypes is an in-memory transport type repository.
+type TransportTypes []entities.TransportType
+
+// Labels is an in-memory label repository.
+type Labels []entities.Label
+
+func (repo Labels) GetLabels() ([]entities.Label, error) {
+	return repo, nil
+}
+
+func (repo Labels) SaveLabel(_ entities.Label) error {
+	return nil
+}
+
+// MapBounds is an in-memory map bounds repository.
+type MapBounds []entities.MapBounds
+
+func (repo MapBounds) GetMapBounds() ([]entities.MapBounds, error) {
+	return repo, nil
+}
+
+func (repo MapBounds) SaveMapBounds(_ entities.MapBounds) error {
+	return nil
+}
+
+func (repo Servers) GetServers() ([]entities.Server, error) {
+	return repo, nil
+}
+
+func (repo Servers) SaveServer(_ entities.Server) error {
+	return nil
+}
+
+func (repo Dimensions) GetDimensions() ([]entities.Dimension, error) {
+	return repo, nil
+}
+
+func (repo Dimensions) SaveDimension(_ entities.Dimension) error {
+	return nil
+}
+
+func (repo Areas) GetAreas() ([]entities.Area, error) {
+	return repo, nil
+}
+
+func (repo Areas) SaveArea(_ entities.Area) error {
+	return nil
+}
+
+func (repo SubAreas) GetSubAreas() ([]entities.SubArea, error) {
+	return repo, nil
+}
+
+func (repo SubAreas) SaveSubArea(_ entities.SubArea) error {
+	return nil
+}
+
+func (repo TransportTypes) GetTransportTypes() ([]entities.TransportType, error) {
+	return repo, nil
+}
+
+func (repo TransportTypes) SaveTransportType(_ entities.TransportType) error {
+	return nil
+}
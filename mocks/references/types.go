@@ -0,0 +1,18 @@
+package references
+
+import (
+	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/subareas"
+	"github.com/kaellybot/kaelly-portals/services/transports"
+)
+
+// Services gathers reference services backed by in-memory repositories.
+type Services struct {
+	Servers    servers.Service
+	Dimensions dimensions.Service
+	Areas      areas.Service
+	SubAreas   subareas.Service
+	Transports transports.Service
+}
@@ -0,0 +1,113 @@
+package snapshots
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+func New(snapshots ...entities.Snapshot) *Repository {
+	repo := Repository{snapshots: make([]entities.Snapshot, 0)}
+	for _, snapshot := range snapshots {
+		_ = repo.SaveSnapshot(&snapshot)
+	}
+	return &repo
+}
+
+// GetSnapshots returns every snapshot saved so far.
+func (repo *Repository) GetSnapshots() []entities.Snapshot {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+	return append([]entities.Snapshot{}, repo.snapshots...)
+}
+
+func (repo *Repository) SaveSnapshot(snapshot *entities.Snapshot) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	for _, existing := range repo.snapshots {
+		if snapshot.Key != "" && existing.Key == snapshot.Key {
+			return nil
+		}
+	}
+
+	repo.lastID++
+	snapshot.ID = repo.lastID
+	repo.snapshots = append(repo.snapshots, *snapshot)
+	return nil
+}
+
+func (repo *Repository) GetContributions(serverID string, since time.Time, limit int,
+) ([]entities.Contribution, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	contributions := make(map[string]*entities.Contribution)
+	for _, snapshot := range repo.snapshots {
+		if snapshot.Reporter == "" || snapshot.ReportedAt.Before(since) ||
+			serverID != "" && snapshot.ServerID != serverID {
+			continue
+		}
+
+		contribution, found := contributions[snapshot.Reporter]
+		if !found {
+			contribution = &entities.Contribution{Reporter: snapshot.Reporter}
+			contributions[snapshot.Reporter] = contribution
+		}
+		contribution.Contributions++
+		if snapshot.ReportedAt.After(contribution.LastReportAt) {
+			contribution.LastReportAt = snapshot.ReportedAt
+		}
+	}
+
+	result := make([]entities.Contribution, 0, len(contributions))
+	for _, contribution := range contributions {
+		result = append(result, *contribution)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Contributions != result[j].Contributions {
+			return result[i].Contributions > result[j].Contributions
+		}
+		return result[i].LastReportAt.After(result[j].LastReportAt)
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (repo *Repository) GetStatistics(serverID, dimensionID string, since time.Time, frequencies int,
+) ([]entities.Statistics, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	snapshots := make([]entities.Snapshot, 0)
+	for _, snapshot := range repo.snapshots {
+		if snapshot.ServerID == serverID && !snapshot.ReportedAt.Before(since) &&
+			(dimensionID == "" || snapshot.DimensionID == dimensionID) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].ReportedAt.Before(snapshots[j].ReportedAt)
+	})
+
+	dimensionIDs := make([]string, 0)
+	dimensionSnapshots := make(map[string][]entities.Snapshot)
+	for _, snapshot := range snapshots {
+		if _, found := dimensionSnapshots[snapshot.DimensionID]; !found {
+			dimensionIDs = append(dimensionIDs, snapshot.DimensionID)
+		}
+		dimensionSnapshots[snapshot.DimensionID] = append(dimensionSnapshots[snapshot.DimensionID], snapshot)
+	}
+	sort.Strings(dimensionIDs)
+
+	result := make([]entities.Statistics, 0, len(dimensionIDs))
+	for _, id := range dimensionIDs {
+		result = append(result, computeStatistics(serverID, id, dimensionSnapshots[id], frequencies))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package snapshots
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// computeStatistics expects the snapshots of a single dimension, oldest first.
+// A position lives until the next one appears, the last one being left out of the average
+// lifetime; its uses are the remaining uses lost between its first and last snapshots.
+func computeStatistics(serverID, dimensionID string, snapshots []entities.Snapshot, frequencies int,
+) entities.Statistics {
+	positions := groupPositions(snapshots)
+	areas := make(map[string]int)
+	subAreas := make(map[string]int)
+	var canopies, uses int64
+	var lifetime time.Duration
+	for i, position := range positions {
+		if position.first.AreaID != "" {
+			areas[position.first.AreaID]++
+		}
+		if position.first.SubAreaID != "" {
+			subAreas[position.first.SubAreaID]++
+		}
+		if position.first.IsInCanopy {
+			canopies++
+		}
+		uses += max(0, position.first.RemainingUses-position.last.RemainingUses)
+		if i+1 < len(positions) {
+			lifetime += positions[i+1].first.ReportedAt.Sub(position.first.ReportedAt)
+		}
+	}
+
+	statistics := entities.Statistics{
+		ServerID:    serverID,
+		DimensionID: dimensionID,
+		Positions:   len(positions),
+		Areas:       getFrequencies(areas, frequencies),
+		SubAreas:    getFrequencies(subAreas, frequencies),
+	}
+
+	if len(positions) > 0 {
+		statistics.CanopyFrequency = float64(canopies) / float64(len(positions))
+		days := max(day, snapshots[len(snapshots)-1].ReportedAt.Sub(snapshots[0].ReportedAt))
+		statistics.AverageUsesPerDay = float64(uses) / (float64(days) / float64(day))
+	}
+	if len(positions) > 1 {
+		statistics.AverageLifetime = lifetime / time.Duration(len(positions)-1)
+	}
+
+	return statistics
+}
+
+func groupPositions(snapshots []entities.Snapshot) []position {
+	positions := make([]position, 0)
+	for _, snapshot := range snapshots {
+		last := len(positions) - 1
+		if last >= 0 && positions[last].last.X == snapshot.X && positions[last].last.Y == snapshot.Y {
+			positions[last].last = snapshot
+			continue
+		}
+		positions = append(positions, position{first: snapshot, last: snapshot})
+	}
+
+	return positions
+}
+
+// getFrequencies returns the most frequent references, most frequent first.
+func getFrequencies(counts map[string]int, limit int) []entities.Frequency {
+	frequencies := make([]entities.Frequency, 0, len(counts))
+	for id, count := range counts {
+		frequencies = append(frequencies, entities.Frequency{ID: id, Positions: count})
+	}
+	sort.Slice(frequencies, func(i, j int) bool {
+		if frequencies[i].Positions != frequencies[j].Positions {
+			return frequencies[i].Positions > frequencies[j].Positions
+		}
+		return frequencies[i].ID < frequencies[j].ID
+	})
+
+	if len(frequencies) > limit {
+		frequencies = frequencies[:limit]
+	}
+	return frequencies
+}
@@ -0,0 +1,23 @@
+package snapshots
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+const day = 24 * time.Hour
+
+// Repository is an in-memory snapshot repository.
+type Repository struct {
+	mutex     sync.Mutex
+	lastID    uint
+	snapshots []entities.Snapshot
+}
+
+// position gathers the consecutive snapshots of a dimension sharing the same coordinates.
+type position struct {
+	first entities.Snapshot
+	last  entities.Snapshot
+}
@@ -0,0 +1,49 @@
+package subscriptions
+
+import (
+	"sort"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+func New(subscriptions ...entities.Subscription) *Repository {
+	repo := Repository{subscriptions: make(map[uint]entities.Subscription)}
+	for _, subscription := range subscriptions {
+		_ = repo.SaveSubscription(&subscription)
+	}
+	return &repo
+}
+
+func (repo *Repository) GetSubscriptions() ([]entities.Subscription, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	subscriptions := make([]entities.Subscription, 0, len(repo.subscriptions))
+	for _, subscription := range repo.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions, nil
+}
+
+func (repo *Repository) SaveSubscription(subscription *entities.Subscription) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if subscription.ID == 0 {
+		repo.lastID++
+		subscription.ID = repo.lastID
+	}
+	repo.subscriptions[subscription.ID] = *subscription
+	return nil
+}
+
+func (repo *Repository) DeleteSubscription(id uint) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	delete(repo.subscriptions, id)
+	return nil
+}
@@ -0,0 +1,14 @@
+package subscriptions
+
+import (
+	"sync"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// Repository is an in-memory subscription repository.
+type Repository struct {
+	mutex         sync.Mutex
+	lastID        uint
+	subscriptions map[uint]entities.Subscription
+}
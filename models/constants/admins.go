@@ -0,0 +1,17 @@
+package constants
+
+import "google.golang.org/protobuf/encoding/protowire"
+
+// AdminPayloadField is the number of the RabbitMQ message field carrying admin commands
+// and their answers, JSON-encoded. kaelly-amqp does not define it, so it is read from and
+// written to the unknown fields of the message, preserved by protobuf.
+const AdminPayloadField protowire.Number = 9000
+
+// Admin commands sent on the admin queue.
+const (
+	AdminCommandFlushCache       = "flush-cache"
+	AdminCommandReloadReferences = "reload-references"
+	AdminCommandPollServer       = "poll-server"
+	AdminCommandToggleSource     = "toggle-source"
+	AdminCommandStats            = "stats"
+)
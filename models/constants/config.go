@@ -24,49 +24,222 @@ const (
 	// RabbitMQ address.
 	RabbitMQAddress = "RABBITMQ_ADDRESS"
 
+	// Boolean; disable to stop requesting Dofus Portals, portal requests then fail.
+	DofusPortalsEnabled = "DOFUS_PORTALS_ENABLED"
+
+	// Dofus Portals base URL.
+	DofusPortalsURL = "DOFUS_PORTALS_URL"
+
+	// Dofus Portals fallback URLs, comma-separated and tried in order when the previous ones fail.
+	DofusPortalsFallbackURLs = "DOFUS_PORTALS_FALLBACK_URLS"
+
 	// Dofus Portals Token.
 	DofusPortalsToken = "DOFUS_PORTALS_TOKEN"
 
+	// File containing Dofus Portals Token, e.g. a mounted secret; read again periodically.
+	// Takes precedence over DOFUS_PORTALS_TOKEN.
+	DofusPortalsTokenFile = "DOFUS_PORTALS_TOKEN_FILE"
+
+	// Dofus Portals secondary Token, used when the primary one is rejected.
+	DofusPortalsSecondaryToken = "DOFUS_PORTALS_SECONDARY_TOKEN"
+
+	// File containing Dofus Portals secondary Token.
+	// Takes precedence over DOFUS_PORTALS_SECONDARY_TOKEN.
+	DofusPortalsSecondaryTokenFile = "DOFUS_PORTALS_SECONDARY_TOKEN_FILE"
+
 	// Timeout to retrieve portals in seconds.
 	DofusPortalsTimeout = "HTTP_TIMEOUT"
 
+	// HTTP mode to reach Dofus Portals, from [live, record, replay].
+	DofusPortalsHTTPMode = "HTTP_MODE"
+
+	// Directory where Dofus Portals responses are recorded and replayed from.
+	DofusPortalsFixtures = "HTTP_FIXTURES_DIR"
+
+	// Duration during which successful replies are kept to answer redelivered requests; 0 disables it.
+	DeduplicationTTL = "DEDUPLICATION_TTL"
+
+	// Number of failed attempts after which a request is quarantined as a dead letter.
+	DeadLetterMaxAttempts = "DEAD_LETTER_MAX_ATTEMPTS"
+
+	// Duration after which dead letters not failing again are purged, whatever their state.
+	DeadLetterRetention = "DEAD_LETTER_RETENTION"
+
+	// Maximum number of dead letters kept; the least recently failing ones are purged beyond.
+	DeadLetterMaxEntries = "DEAD_LETTER_MAX_ENTRIES"
+
+	// Maximum number of portal requests per user over QUOTA_WINDOW; 0 disables quotas.
+	QuotaUserLimit = "QUOTA_USER_LIMIT"
+
+	// Sliding window over which quotas are counted.
+	QuotaWindow = "QUOTA_WINDOW"
+
+	// Answer of requests over quota, from [cache, reject]: cache answers the last known
+	// positions if any, reject always fails.
+	QuotaMode = "QUOTA_MODE"
+
+	// Interval between two polls of the servers having subscriptions; 0 disables polling.
+	PollInterval = "POLL_INTERVAL"
+
+	// Age above which a portal position is flagged as probably outdated.
+	PositionMaxAge = "POSITION_MAX_AGE"
+
+	// Handling of positions failing map bounds validation, from [annotate, drop]: annotate
+	// keeps them with a null confidence, drop removes the position while keeping the portal.
+	SuspiciousPositionMode = "SUSPICIOUS_POSITION_MODE"
+
+	// Number of retries of the initial MySQL and RabbitMQ connections, before giving up.
+	StartupRetries = "STARTUP_RETRIES"
+
+	// Delay before retrying an initial connection, doubled after each failure up to 30 seconds.
+	StartupRetryDelay = "STARTUP_RETRY_DELAY"
+
+	// Local file holding the last reference data loaded from MySQL, used when MySQL is unreachable
+	// at startup; empty to disable. Read as YAML with a .yaml or .yml extension, as JSON otherwise.
+	ReferenceSnapshotFile = "REFERENCE_SNAPSHOT_FILE"
+
+	// Source of reference data, from [mysql, file]: file always loads REFERENCE_SNAPSHOT_FILE.
+	ReferenceSource = "REFERENCE_SOURCE"
+
+	// Queues of portal requests, comma-separated and formatted as queue:routingKey[:workers[:sources]]:
+	// workers bounds the requests treated at once (0 for no limit), sources are +-separated
+	// and restrict the sources answering the queue (every source when omitted).
+	RequestBindings = "REQUEST_BINDINGS"
+
+	// Time given to in-flight requests to be treated on shutdown, before closing connections.
+	ShutdownGracePeriod = "SHUTDOWN_GRACE_PERIOD"
+
 	// Probe port.
 	ProbePort = "PROBE_PORT"
 
 	// Metric port.
 	MetricPort = "METRIC_PORT"
 
+	// Boolean; expose an HTTP API mirroring portal requests.
+	APIEnabled = "API_ENABLED"
+
+	// HTTP API port.
+	APIPort = "API_PORT"
+
+	// Token required by admin commands, sent on the admin queue or to the admin routes of the HTTP API;
+	// empty to disable them.
+	AdminToken = "ADMIN_TOKEN"
+
+	// Queue of admin commands, bound to ADMIN_ROUTING_KEY on the requests exchange when ADMIN_TOKEN is set.
+	AdminQueue = "ADMIN_QUEUE"
+
+	// Routing key of admin commands.
+	AdminRoutingKey = "ADMIN_ROUTING_KEY"
+
+	// Boolean; export OpenTelemetry traces to an OTLP collector over HTTP.
+	TracingEnabled = "TRACING_ENABLED"
+
+	// OTLP collector endpoint with the following format: HOST:PORT.
+	TracingEndpoint = "TRACING_ENDPOINT"
+
+	// Boolean; reach the OTLP collector without TLS.
+	TracingInsecure = "TRACING_INSECURE"
+
+	// Ratio of traces sampled when not already decided by the caller, between 0 and 1.
+	TracingSampleRatio = "TRACING_SAMPLE_RATIO"
+
 	// Zerolog values from [trace, debug, info, warn, error, fatal, panic].
 	LogLevel = "LOG_LEVEL"
 
 	// Boolean; used to register commands at development guild level or globally.
 	Production = "PRODUCTION"
 
-	defaultMySQLURL            = "localhost:3306"
-	defaultMySQLUser           = ""
-	defaultMySQLPassword       = ""
-	defaultMySQLDatabase       = "kaellybot"
-	defaultRabbitMQAddress     = "amqp://localhost:5672"
-	defaultDofusPortalsToken   = ""
-	defaultDofusPortalsTimeout = 60 * time.Second
-	defaultProbePort           = 9090
-	defaultMetricPort          = 2112
-	defaultLogLevel            = zerolog.InfoLevel
-	defaultProduction          = false
+	defaultMySQLURL                       = "localhost:3306"
+	defaultMySQLUser                      = ""
+	defaultMySQLPassword                  = ""
+	defaultMySQLDatabase                  = "kaellybot"
+	defaultRabbitMQAddress                = "amqp://localhost:5672"
+	defaultDofusPortalsEnabled            = true
+	defaultDofusPortalsURL                = "https://api.dofus-portals.fr"
+	defaultDofusPortalsFallbackURLs       = ""
+	defaultDofusPortalsTokenFile          = ""
+	defaultDofusPortalsSecondaryToken     = ""
+	defaultDofusPortalsSecondaryTokenFile = ""
+	defaultDofusPortalsToken              = ""
+	defaultDofusPortalsTimeout            = 60 * time.Second
+	defaultDofusPortalsHTTPMode           = HTTPModeLive
+	defaultDofusPortalsFixtures           = "fixtures"
+	defaultDeduplicationTTL               = 5 * time.Minute
+	defaultDeadLetterMaxAttempts          = 3
+	defaultDeadLetterRetention            = 7 * 24 * time.Hour
+	defaultDeadLetterMaxEntries           = 1000
+	defaultQuotaUserLimit                 = 0
+	defaultQuotaWindow                    = time.Minute
+	defaultQuotaMode                      = QuotaModeCache
+	defaultPollInterval                   = 5 * time.Minute
+	defaultPositionMaxAge                 = 24 * time.Hour
+	defaultSuspiciousPositionMode         = SuspiciousModeAnnotate
+	defaultStartupRetries                 = 10
+	defaultStartupRetryDelay              = time.Second
+	defaultReferenceSnapshotFile          = ""
+	defaultReferenceSource                = ReferenceSourceMySQL
+	defaultRequestBindings                = "portals-requests:requests.portals"
+	defaultShutdownGracePeriod            = 30 * time.Second
+	defaultProbePort                      = 9090
+	defaultMetricPort                     = 2112
+	defaultAPIEnabled                     = false
+	defaultAPIPort                        = 8080
+	defaultAdminToken                     = ""
+	defaultAdminQueue                     = "portals-admin"
+	defaultAdminRoutingKey                = "admin.portals"
+	defaultTracingEnabled                 = false
+	defaultTracingEndpoint                = "localhost:4318"
+	defaultTracingInsecure                = false
+	defaultTracingSampleRatio             = 1.0
+	defaultLogLevel                       = zerolog.InfoLevel
+	defaultProduction                     = false
 )
 
 func GetDefaultConfigValues() map[string]any {
 	return map[string]any{
-		MySQLURL:            defaultMySQLURL,
-		MySQLUser:           defaultMySQLUser,
-		MySQLPassword:       defaultMySQLPassword,
-		MySQLDatabase:       defaultMySQLDatabase,
-		RabbitMQAddress:     defaultRabbitMQAddress,
-		DofusPortalsToken:   defaultDofusPortalsToken,
-		DofusPortalsTimeout: defaultDofusPortalsTimeout,
-		ProbePort:           defaultProbePort,
-		MetricPort:          defaultMetricPort,
-		LogLevel:            defaultLogLevel.String(),
-		Production:          defaultProduction,
+		MySQLURL:                       defaultMySQLURL,
+		MySQLUser:                      defaultMySQLUser,
+		MySQLPassword:                  defaultMySQLPassword,
+		MySQLDatabase:                  defaultMySQLDatabase,
+		RabbitMQAddress:                defaultRabbitMQAddress,
+		DofusPortalsEnabled:            defaultDofusPortalsEnabled,
+		DofusPortalsURL:                defaultDofusPortalsURL,
+		DofusPortalsFallbackURLs:       defaultDofusPortalsFallbackURLs,
+		DofusPortalsTokenFile:          defaultDofusPortalsTokenFile,
+		DofusPortalsSecondaryToken:     defaultDofusPortalsSecondaryToken,
+		DofusPortalsSecondaryTokenFile: defaultDofusPortalsSecondaryTokenFile,
+		DofusPortalsToken:              defaultDofusPortalsToken,
+		DofusPortalsTimeout:            defaultDofusPortalsTimeout,
+		DofusPortalsHTTPMode:           defaultDofusPortalsHTTPMode,
+		DofusPortalsFixtures:           defaultDofusPortalsFixtures,
+		DeduplicationTTL:               defaultDeduplicationTTL,
+		DeadLetterMaxAttempts:          defaultDeadLetterMaxAttempts,
+		DeadLetterRetention:            defaultDeadLetterRetention,
+		DeadLetterMaxEntries:           defaultDeadLetterMaxEntries,
+		QuotaUserLimit:                 defaultQuotaUserLimit,
+		QuotaWindow:                    defaultQuotaWindow,
+		QuotaMode:                      defaultQuotaMode,
+		PollInterval:                   defaultPollInterval,
+		PositionMaxAge:                 defaultPositionMaxAge,
+		SuspiciousPositionMode:         defaultSuspiciousPositionMode,
+		StartupRetries:                 defaultStartupRetries,
+		StartupRetryDelay:              defaultStartupRetryDelay,
+		ReferenceSnapshotFile:          defaultReferenceSnapshotFile,
+		ReferenceSource:                defaultReferenceSource,
+		RequestBindings:                defaultRequestBindings,
+		ShutdownGracePeriod:            defaultShutdownGracePeriod,
+		ProbePort:                      defaultProbePort,
+		MetricPort:                     defaultMetricPort,
+		APIEnabled:                     defaultAPIEnabled,
+		APIPort:                        defaultAPIPort,
+		AdminToken:                     defaultAdminToken,
+		AdminQueue:                     defaultAdminQueue,
+		AdminRoutingKey:                defaultAdminRoutingKey,
+		TracingEnabled:                 defaultTracingEnabled,
+		TracingEndpoint:                defaultTracingEndpoint,
+		TracingInsecure:                defaultTracingInsecure,
+		TracingSampleRatio:             defaultTracingSampleRatio,
+		LogLevel:                       defaultLogLevel.String(),
+		Production:                     defaultProduction,
 	}
 }
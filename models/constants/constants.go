@@ -5,5 +5,4 @@ const (
 	InternalName     = "Kaelly-Portals"
 	Version          = "2.0.0"
 	RabbitMQClientID = InternalName
-	DofusPortalsURL  = "https://api.dofus-portals.fr"
 )
@@ -11,6 +11,23 @@ const (
 	LogReplyTo         = "replyTo"
 	LogSubAreaID       = "subAreaID"
 	LogTransportTypeID = "transportTypeID"
+	LogQueue           = "queue"
+	LogEndpoint        = "endpoint"
+	LogStatusCode      = "statusCode"
+	LogToken           = "token"
+	LogDeadLetterID    = "deadLetterID"
+	LogAttempts        = "attempts"
+	LogQuarantined     = "quarantined"
+	LogUserID          = "userID"
+	LogSubscriptionID  = "subscriptionID"
+	LogMode            = "mode"
+	LogStep            = "step"
+	LogDuration        = "duration"
+	LogRoute           = "route"
+	LogWorkers         = "workers"
+	LogGame            = "game"
+	LogCommand         = "command"
+	LogArguments       = "arguments"
 
 	LogLevelFallback = zerolog.InfoLevel
 )
@@ -0,0 +1,26 @@
+package constants
+
+// HTTP modes used to reach Dofus Portals.
+const (
+	HTTPModeLive   = "live"
+	HTTPModeRecord = "record"
+	HTTPModeReplay = "replay"
+)
+
+// Behaviours once a user has reached its request quota.
+const (
+	QuotaModeCache  = "cache"
+	QuotaModeReject = "reject"
+)
+
+// Behaviours when a position does not fit the map bounds dataset.
+const (
+	SuspiciousModeAnnotate = "annotate"
+	SuspiciousModeDrop     = "drop"
+)
+
+// Sources the reference data is loaded from.
+const (
+	ReferenceSourceMySQL = "mysql"
+	ReferenceSourceFile  = "file"
+)
@@ -0,0 +1,49 @@
+package constants
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+	PeriodAll   Period = "all"
+)
+
+var ErrUnknownPeriod = errors.New("unknown period, expected one of day, week, month or all")
+
+// ParsePeriod returns the period named by value, PeriodAll when empty.
+func ParsePeriod(value string) (Period, error) {
+	if value == "" {
+		return PeriodAll, nil
+	}
+
+	period := Period(value)
+	if _, err := GetPeriodStart(period, time.Now()); err != nil {
+		return "", err
+	}
+
+	return period, nil
+}
+
+// GetPeriodStart returns the beginning of a period ending now;
+// the zero time is returned for PeriodAll.
+func GetPeriodStart(period Period, now time.Time) (time.Time, error) {
+	switch period {
+	case PeriodDay:
+		return now.AddDate(0, 0, -1), nil
+	case PeriodWeek:
+		return now.AddDate(0, 0, -7), nil
+	case PeriodMonth:
+		return now.AddDate(0, -1, 0), nil
+	case PeriodAll:
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("%w: %s", ErrUnknownPeriod, period)
+	}
+}
@@ -1,15 +1,40 @@
 package constants
 
+import (
+	"slices"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+)
+
 type Source struct {
 	Name string
 	Icon string
 	URL  string
+	// Games are the game flavours whose portals the source knows.
+	Games []amqp.Game
+}
+
+// GetSources returns every source portals are retrieved from.
+func GetSources() []Source {
+	return []Source{GetDofusPortalsSource()}
 }
 
 func GetDofusPortalsSource() Source {
 	return Source{
-		Name: "dofus-portals.fr",
-		Icon: "https://i.imgur.com/j8p3M2D.png",
-		URL:  "https://dofus-portals.fr",
+		Name:  "dofus-portals.fr",
+		Icon:  "https://i.imgur.com/j8p3M2D.png",
+		URL:   "https://dofus-portals.fr",
+		Games: []amqp.Game{amqp.Game_DOFUS_GAME},
 	}
 }
+
+// MatchesGame tells if a reference, such as a server, belongs to the game;
+// ANY_GAME matches every game, on either side.
+func MatchesGame(referenceGame, game amqp.Game) bool {
+	return referenceGame == amqp.Game_ANY_GAME || game == amqp.Game_ANY_GAME || referenceGame == game
+}
+
+// Supports tells if the source knows the portals of the game; ANY_GAME is supported by every source.
+func (source Source) Supports(game amqp.Game) bool {
+	return game == amqp.Game_ANY_GAME || slices.Contains(source.Games, game)
+}
@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+type DeadLetter struct {
+	ID            uint `gorm:"primaryKey"`
+	CorrelationID string
+	ReplyTo       string
+	RoutingKey    string
+	Payload       []byte
+	Error         string
+	Attempts      int
+	Quarantined   bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
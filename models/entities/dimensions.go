@@ -1,6 +1,10 @@
 package entities
 
+import amqp "github.com/kaellybot/kaelly-amqp"
+
+// Dimension of a game flavour; ANY_GAME for dimensions shared by every flavour.
 type Dimension struct {
-	ID             string `gorm:"primaryKey"`
-	DofusPortalsID string `gorm:"unique"`
+	ID             string    `gorm:"primaryKey" json:"id"`
+	DofusPortalsID string    `gorm:"unique" json:"dofusPortalsId"`
+	Game           amqp.Game `json:"game"`
 }
@@ -0,0 +1,11 @@
+package entities
+
+import amqp "github.com/kaellybot/kaelly-amqp"
+
+// Label of a reference, such as a dimension or an area, in a given language.
+type Label struct {
+	ReferenceType string        `gorm:"primaryKey" json:"referenceType"`
+	ReferenceID   string        `gorm:"primaryKey" json:"referenceId"`
+	Language      amqp.Language `gorm:"primaryKey" json:"language"`
+	Label         string        `json:"label"`
+}
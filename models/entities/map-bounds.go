@@ -0,0 +1,13 @@
+package entities
+
+// MapBounds of an area, or of one of its sub-areas when SubAreaID is set, in map
+// coordinates. An area or a sub-area can be made of several bounds.
+type MapBounds struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	AreaID    string `gorm:"index" json:"areaId"`
+	SubAreaID string `gorm:"index" json:"subAreaId,omitempty"`
+	MinX      int64  `json:"minX"`
+	MaxX      int64  `json:"maxX"`
+	MinY      int64  `json:"minY"`
+	MaxY      int64  `json:"maxY"`
+}
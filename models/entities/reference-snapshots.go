@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// ReferenceSnapshot gathers every reference data, to be stored outside of MySQL.
+type ReferenceSnapshot struct {
+	Version        int             `json:"version"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	Servers        []Server        `json:"servers"`
+	Dimensions     []Dimension     `json:"dimensions"`
+	Areas          []Area          `json:"areas"`
+	SubAreas       []SubArea       `json:"subAreas"`
+	TransportTypes []TransportType `json:"transportTypes"`
+	MapBounds      []MapBounds     `json:"mapBounds"`
+	Labels         []Label         `json:"labels"`
+}
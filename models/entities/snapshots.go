@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// Snapshot of a portal position as reported by the community, recorded once per report.
+type Snapshot struct {
+	ID              uint   `gorm:"primaryKey"`
+	Key             string `gorm:"unique"`
+	ServerID        string `gorm:"index"`
+	DimensionID     string
+	X               int64
+	Y               int64
+	IsInCanopy      bool
+	AreaID          string
+	SubAreaID       string
+	TransportTypeID string
+	RemainingUses   int64
+	Reporter        string `gorm:"index"`
+	Source          string
+	ReportedAt      time.Time `gorm:"index"`
+	CreatedAt       time.Time
+}
+
+// Contribution counts the snapshots reported by a community member.
+type Contribution struct {
+	Reporter      string
+	Contributions int64
+	LastReportAt  time.Time
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// Statistics of the portal positions of a server dimension over a period, computed from snapshots.
+type Statistics struct {
+	ServerID          string
+	DimensionID       string
+	Positions         int
+	Areas             []Frequency
+	SubAreas          []Frequency
+	AverageLifetime   time.Duration
+	AverageUsesPerDay float64
+	CanopyFrequency   float64
+}
+
+// Frequency counts the positions found in a reference, such as an area.
+type Frequency struct {
+	ID        string
+	Positions int
+}
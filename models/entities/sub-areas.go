@@ -1,6 +1,6 @@
 package entities
 
 type SubArea struct {
-	ID             string `gorm:"primaryKey"`
-	DofusPortalsID string `gorm:"unique"`
+	ID             string `gorm:"primaryKey" json:"id"`
+	DofusPortalsID string `gorm:"unique" json:"dofusPortalsId"`
 }
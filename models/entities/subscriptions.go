@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Subscription of a guild channel to the portal changes of a server, optionally
+// restricted to one dimension. Zero-valued filters are not applied.
+type Subscription struct {
+	ID               uint `gorm:"primaryKey"`
+	GuildID          string
+	ChannelID        string
+	ServerID         string
+	DimensionID      string
+	MinRemainingUses int64
+	TransportTypeID  string
+	CreatedAt        time.Time
+}
@@ -0,0 +1,160 @@
+package mappers
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+)
+
+func TestMapPortal(t *testing.T) {
+	refs := references.New(
+		references.Servers{{ID: "1", DofusPortalsID: "agride"}},
+		references.Dimensions{{ID: "enu", DofusPortalsID: "enutrosor"}},
+		references.Areas{{ID: "area-astrub", DofusPortalsID: "astrub"}},
+		references.SubAreas{{ID: "subarea-astrub", DofusPortalsID: "cite_astrub"}},
+		references.TransportTypes{{ID: "transport-zaap", DofusPortalsID: "zaap"}},
+	)
+
+	now := time.Now()
+	remainingUses := float32(42)
+	isInCanopy := true
+
+	tests := []struct {
+		name     string
+		portal   dofusportals.Portal
+		expected *amqp.PortalPositionAnswer_PortalPosition
+	}{
+		{
+			name:   "unknown position",
+			portal: dofusportals.Portal{Server: "agride", Dimension: "enutrosor"},
+			expected: &amqp.PortalPositionAnswer_PortalPosition{
+				ServerId:    "1",
+				DimensionId: "enu",
+			},
+		},
+		{
+			name:   "unmapped IDs",
+			portal: dofusportals.Portal{Server: "unknown", Dimension: "unknown"},
+			expected: &amqp.PortalPositionAnswer_PortalPosition{
+				ServerId:    "unknown",
+				DimensionId: "unknown",
+			},
+		},
+		{
+			name: "complete position",
+			portal: dofusportals.Portal{
+				Server:        "agride",
+				Dimension:     "enutrosor",
+				RemainingUses: &remainingUses,
+				CreatedBy:     &dofusportals.User{Name: "creator"},
+				UpdatedBy:     &dofusportals.User{Name: "updater"},
+				CreatedAt:     &now,
+				UpdatedAt:     &now,
+				Position: &dofusportals.Position{
+					X:          5,
+					Y:          -18,
+					IsInCanopy: &isInCanopy,
+					Transport: &dofusportals.Transport{
+						Area: "astrub", SubArea: "cite_astrub", Type: dofusportals.Zaap, X: 4, Y: -19,
+					},
+					ConditionalTransport: &dofusportals.Transport{
+						Area: "other", SubArea: "other", Type: dofusportals.Skis, X: 1, Y: 2,
+					},
+				},
+			},
+			expected: &amqp.PortalPositionAnswer_PortalPosition{
+				ServerId:      "1",
+				DimensionId:   "enu",
+				RemainingUses: 42,
+				CreatedBy:     "creator",
+				UpdatedBy:     "updater",
+				Position: &amqp.PortalPositionAnswer_PortalPosition_Position{
+					X:          5,
+					Y:          -18,
+					IsInCanopy: true,
+					Transport: &amqp.PortalPositionAnswer_PortalPosition_Position_Transport{
+						AreaId: "area-astrub", SubAreaId: "subarea-astrub", TypeId: "transport-zaap", X: 4, Y: -19,
+					},
+					ConditionalTransport: &amqp.PortalPositionAnswer_PortalPosition_Position_Transport{
+						AreaId: "other", SubAreaId: "other", TypeId: "skis", X: 1, Y: 2,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := MapPortal(test.portal, refs.Servers, refs.Dimensions,
+				refs.Areas, refs.SubAreas, refs.Transports)
+
+			if result.GetServerId() != test.expected.GetServerId() ||
+				result.GetDimensionId() != test.expected.GetDimensionId() {
+				t.Errorf("expected IDs %s/%s, got %s/%s", test.expected.GetServerId(),
+					test.expected.GetDimensionId(), result.GetServerId(), result.GetDimensionId())
+			}
+			if result.GetRemainingUses() != test.expected.GetRemainingUses() {
+				t.Errorf("expected %d remaining uses, got %d",
+					test.expected.GetRemainingUses(), result.GetRemainingUses())
+			}
+			if result.GetCreatedBy() != test.expected.GetCreatedBy() ||
+				result.GetUpdatedBy() != test.expected.GetUpdatedBy() {
+				t.Errorf("unexpected users: %s/%s", result.GetCreatedBy(), result.GetUpdatedBy())
+			}
+			if (result.GetCreatedAt() == nil) != (test.portal.CreatedAt == nil) {
+				t.Errorf("unexpected creation date: %v", result.GetCreatedAt())
+			}
+			if result.GetSource().GetName() == "" {
+				t.Errorf("source is not filled")
+			}
+			comparePosition(t, test.expected.GetPosition(), result.GetPosition())
+		})
+	}
+}
+
+func TestMapPortalAnswer(t *testing.T) {
+	positions := []*amqp.PortalPositionAnswer_PortalPosition{{ServerId: "1"}, {ServerId: "2"}}
+	answer := MapPortalAnswer(positions, amqp.Language_FR)
+
+	if answer.GetType() != amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER {
+		t.Errorf("unexpected type: %v", answer.GetType())
+	}
+	if answer.GetStatus() != amqp.RabbitMQMessage_SUCCESS {
+		t.Errorf("unexpected status: %v", answer.GetStatus())
+	}
+	if answer.GetLanguage() != amqp.Language_FR {
+		t.Errorf("unexpected language: %v", answer.GetLanguage())
+	}
+	if len(answer.GetPortalPositionAnswer().GetPositions()) != len(positions) {
+		t.Errorf("unexpected positions: %v", answer.GetPortalPositionAnswer().GetPositions())
+	}
+}
+
+func comparePosition(t *testing.T, expected, result *amqp.PortalPositionAnswer_PortalPosition_Position) {
+	t.Helper()
+	if (expected == nil) != (result == nil) {
+		t.Fatalf("expected position %v, got %v", expected, result)
+	}
+	if expected == nil {
+		return
+	}
+
+	if result.GetX() != expected.GetX() || result.GetY() != expected.GetY() ||
+		result.GetIsInCanopy() != expected.GetIsInCanopy() {
+		t.Errorf("expected position %v, got %v", expected, result)
+	}
+	compareTransport(t, expected.GetTransport(), result.GetTransport())
+	compareTransport(t, expected.GetConditionalTransport(), result.GetConditionalTransport())
+}
+
+func compareTransport(t *testing.T, expected, result *amqp.PortalPositionAnswer_PortalPosition_Position_Transport) {
+	t.Helper()
+	if result.GetAreaId() != expected.GetAreaId() || result.GetSubAreaId() != expected.GetSubAreaId() ||
+		result.GetTypeId() != expected.GetTypeId() || result.GetX() != expected.GetX() ||
+		result.GetY() != expected.GetY() {
+		t.Errorf("expected transport %v, got %v", expected, result)
+	}
+}
@@ -14,3 +14,7 @@ func (repo *Impl) GetAreas() ([]entities.Area, error) {
 	response := repo.db.GetDB().Model(&entities.Area{}).Find(&areas)
 	return areas, response.Error
 }
+
+func (repo *Impl) SaveArea(area entities.Area) error {
+	return repo.db.GetDB().Save(&area).Error
+}
@@ -7,6 +7,7 @@ import (
 
 type Repository interface {
 	GetAreas() ([]entities.Area, error)
+	SaveArea(area entities.Area) error
 }
 
 type Impl struct {
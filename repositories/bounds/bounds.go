@@ -0,0 +1,20 @@
+package bounds
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+func New(db databases.MySQLConnection) *Impl {
+	return &Impl{db: db}
+}
+
+func (repo *Impl) GetMapBounds() ([]entities.MapBounds, error) {
+	var mapBounds []entities.MapBounds
+	response := repo.db.GetDB().Model(&entities.MapBounds{}).Find(&mapBounds)
+	return mapBounds, response.Error
+}
+
+func (repo *Impl) SaveMapBounds(mapBounds entities.MapBounds) error {
+	return repo.db.GetDB().Save(&mapBounds).Error
+}
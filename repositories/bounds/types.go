@@ -0,0 +1,15 @@
+package bounds
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+type Repository interface {
+	GetMapBounds() ([]entities.MapBounds, error)
+	SaveMapBounds(mapBounds entities.MapBounds) error
+}
+
+type Impl struct {
+	db databases.MySQLConnection
+}
@@ -0,0 +1,32 @@
+package deadletters
+
+import (
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+func New(db databases.MySQLConnection) *Impl {
+	return &Impl{db: db}
+}
+
+func (repo *Impl) GetDeadLetters() ([]entities.DeadLetter, error) {
+	var deadLetters []entities.DeadLetter
+	response := repo.db.GetDB().Model(&entities.DeadLetter{}).Find(&deadLetters)
+	return deadLetters, response.Error
+}
+
+// SaveDeadLetter inserts or updates a dead letter; its ID is set on insertion.
+func (repo *Impl) SaveDeadLetter(deadLetter *entities.DeadLetter) error {
+	return repo.db.GetDB().Save(deadLetter).Error
+}
+
+func (repo *Impl) DeleteDeadLetter(id uint) error {
+	return repo.db.GetDB().Delete(&entities.DeadLetter{}, id).Error
+}
+
+// DeleteDeadLettersBefore deletes the dead letters not updated since date.
+func (repo *Impl) DeleteDeadLettersBefore(date time.Time) error {
+	return repo.db.GetDB().Where("updated_at < ?", date).Delete(&entities.DeadLetter{}).Error
+}
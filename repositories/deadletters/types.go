@@ -0,0 +1,19 @@
+package deadletters
+
+import (
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+type Repository interface {
+	GetDeadLetters() ([]entities.DeadLetter, error)
+	SaveDeadLetter(deadLetter *entities.DeadLetter) error
+	DeleteDeadLetter(id uint) error
+	DeleteDeadLettersBefore(date time.Time) error
+}
+
+type Impl struct {
+	db databases.MySQLConnection
+}
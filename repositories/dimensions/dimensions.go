@@ -14,3 +14,7 @@ func (repo *Impl) GetDimensions() ([]entities.Dimension, error) {
 	response := repo.db.GetDB().Model(&entities.Dimension{}).Find(&dimensions)
 	return dimensions, response.Error
 }
+
+func (repo *Impl) SaveDimension(dimension entities.Dimension) error {
+	return repo.db.GetDB().Save(&dimension).Error
+}
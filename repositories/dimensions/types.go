@@ -7,6 +7,7 @@ import (
 
 type Repository interface {
 	GetDimensions() ([]entities.Dimension, error)
+	SaveDimension(dimension entities.Dimension) error
 }
 
 type Impl struct {
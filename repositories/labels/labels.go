@@ -0,0 +1,20 @@
+package labels
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+func New(db databases.MySQLConnection) *Impl {
+	return &Impl{db: db}
+}
+
+func (repo *Impl) GetLabels() ([]entities.Label, error) {
+	var labels []entities.Label
+	response := repo.db.GetDB().Model(&entities.Label{}).Find(&labels)
+	return labels, response.Error
+}
+
+func (repo *Impl) SaveLabel(label entities.Label) error {
+	return repo.db.GetDB().Save(&label).Error
+}
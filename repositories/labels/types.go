@@ -0,0 +1,15 @@
+package labels
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+type Repository interface {
+	GetLabels() ([]entities.Label, error)
+	SaveLabel(label entities.Label) error
+}
+
+type Impl struct {
+	db databases.MySQLConnection
+}
@@ -0,0 +1,136 @@
+package references
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/invopop/yaml"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+func New(path string) *Impl {
+	return &Impl{path: path}
+}
+
+// Load reads the snapshot file, as YAML if its extension is .yaml or .yml, as JSON otherwise;
+// reference data is then served from memory.
+func (repo *Impl) Load() error {
+	data, err := os.ReadFile(repo.path)
+	if err != nil {
+		return err
+	}
+
+	var snapshot entities.ReferenceSnapshot
+	if repo.isYAML() {
+		err = yaml.Unmarshal(data, &snapshot)
+	} else {
+		err = json.Unmarshal(data, &snapshot)
+	}
+	if err != nil {
+		return err
+	}
+
+	if snapshot.Version != SnapshotVersion {
+		return fmt.Errorf("%w: %d", errUnsupportedVersion, snapshot.Version)
+	}
+
+	repo.snapshot = snapshot
+	return nil
+}
+
+// Save writes the snapshot file in the format given by its extension,
+// replacing the previous one only once fully written.
+func (repo *Impl) Save(snapshot entities.ReferenceSnapshot) error {
+	snapshot.Version = SnapshotVersion
+	var data []byte
+	var err error
+	if repo.isYAML() {
+		data, err = yaml.Marshal(snapshot)
+	} else {
+		data, err = json.MarshalIndent(snapshot, "", jsonIndent)
+	}
+	if err != nil {
+		return err
+	}
+
+	tmpPath := repo.path + tmpExtension
+	if err = os.WriteFile(tmpPath, data, filePermission); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpPath, repo.path); err != nil {
+		return err
+	}
+
+	repo.snapshot = snapshot
+	return nil
+}
+
+// GetSnapshot returns the reference data loaded or saved last.
+func (repo *Impl) GetSnapshot() entities.ReferenceSnapshot {
+	return repo.snapshot
+}
+
+func (repo *Impl) isYAML() bool {
+	extension := strings.ToLower(filepath.Ext(repo.path))
+	return extension == yamlExtension || extension == ymlExtension
+}
+
+func (repo *Impl) GetServers() ([]entities.Server, error) {
+	return repo.snapshot.Servers, nil
+}
+
+func (repo *Impl) SaveServer(_ entities.Server) error {
+	return errReadOnly
+}
+
+func (repo *Impl) GetDimensions() ([]entities.Dimension, error) {
+	return repo.snapshot.Dimensions, nil
+}
+
+func (repo *Impl) SaveDimension(_ entities.Dimension) error {
+	return errReadOnly
+}
+
+func (repo *Impl) GetAreas() ([]entities.Area, error) {
+	return repo.snapshot.Areas, nil
+}
+
+func (repo *Impl) SaveArea(_ entities.Area) error {
+	return errReadOnly
+}
+
+func (repo *Impl) GetSubAreas() ([]entities.SubArea, error) {
+	return repo.snapshot.SubAreas, nil
+}
+
+func (repo *Impl) SaveSubArea(_ entities.SubArea) error {
+	return errReadOnly
+}
+
+func (repo *Impl) GetTransportTypes() ([]entities.TransportType, error) {
+	return repo.snapshot.TransportTypes, nil
+}
+
+func (repo *Impl) SaveTransportType(_ entities.TransportType) error {
+	return errReadOnly
+}
+
+func (repo *Impl) GetMapBounds() ([]entities.MapBounds, error) {
+	return repo.snapshot.MapBounds, nil
+}
+
+func (repo *Impl) SaveMapBounds(_ entities.MapBounds) error {
+	return errReadOnly
+}
+
+func (repo *Impl) GetLabels() ([]entities.Label, error) {
+	return repo.snapshot.Labels, nil
+}
+
+func (repo *Impl) SaveLabel(_ entities.Label) error {
+	return errReadOnly
+}
@@ -0,0 +1,47 @@
+package references
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "references.json")
+	err := New(path).Save(entities.ReferenceSnapshot{
+		Servers:    []entities.Server{{ID: "1", DofusPortalsID: "agride"}},
+		Dimensions: []entities.Dimension{{ID: "enu", DofusPortalsID: "enutrosor"}},
+	})
+	if err != nil {
+		t.Fatalf("cannot save snapshot: %v", err)
+	}
+
+	repo := New(path)
+	if err = repo.Load(); err != nil {
+		t.Fatalf("cannot load snapshot: %v", err)
+	}
+
+	servers, _ := repo.GetServers()
+	dimensions, _ := repo.GetDimensions()
+	if len(servers) != 1 || servers[0].DofusPortalsID != "agride" || len(dimensions) != 1 {
+		t.Errorf("unexpected reference data: %+v, %+v", servers, dimensions)
+	}
+
+	if err = repo.SaveServer(entities.Server{ID: "2"}); !errors.Is(err, errReadOnly) {
+		t.Errorf("expected read-only error, got %v", err)
+	}
+}
+
+func TestLoadUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "references.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), filePermission); err != nil {
+		t.Fatalf("cannot write snapshot: %v", err)
+	}
+
+	if err := New(path).Load(); !errors.Is(err, errUnsupportedVersion) {
+		t.Errorf("expected unsupported version error, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package references
+
+import (
+	"errors"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+const (
+	// SnapshotVersion is the version of the reference snapshot format written by this repository.
+	SnapshotVersion = 1
+
+	filePermission = 0o600
+	tmpExtension   = ".tmp"
+	yamlExtension  = ".yaml"
+	ymlExtension   = ".yml"
+	jsonIndent     = "  "
+)
+
+var (
+	errReadOnly           = errors.New("reference snapshot file is read-only")
+	errUnsupportedVersion = errors.New("unsupported reference snapshot version")
+)
+
+// Repository reads and writes reference data from a local snapshot file instead of MySQL.
+type Repository interface {
+	Load() error
+	Save(snapshot entities.ReferenceSnapshot) error
+	GetSnapshot() entities.ReferenceSnapshot
+	GetServers() ([]entities.Server, error)
+	SaveServer(server entities.Server) error
+	GetDimensions() ([]entities.Dimension, error)
+	SaveDimension(dimension entities.Dimension) error
+	GetAreas() ([]entities.Area, error)
+	SaveArea(area entities.Area) error
+	GetSubAreas() ([]entities.SubArea, error)
+	SaveSubArea(subArea entities.SubArea) error
+	GetTransportTypes() ([]entities.TransportType, error)
+	SaveTransportType(transportType entities.TransportType) error
+	GetMapBounds() ([]entities.MapBounds, error)
+	SaveMapBounds(mapBounds entities.MapBounds) error
+	GetLabels() ([]entities.Label, error)
+	SaveLabel(label entities.Label) error
+}
+
+type Impl struct {
+	path     string
+	snapshot entities.ReferenceSnapshot
+}
@@ -14,3 +14,7 @@ func (repo *Impl) GetServers() ([]entities.Server, error) {
 	response := repo.db.GetDB().Model(&entities.Server{}).Find(&servers)
 	return servers, response.Error
 }
+
+func (repo *Impl) SaveServer(server entities.Server) error {
+	return repo.db.GetDB().Save(&server).Error
+}
@@ -7,6 +7,7 @@ import (
 
 type Repository interface {
 	GetServers() ([]entities.Server, error)
+	SaveServer(server entities.Server) error
 }
 
 type Impl struct {
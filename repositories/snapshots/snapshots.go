@@ -0,0 +1,116 @@
+package snapshots
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+	"gorm.io/gorm/clause"
+)
+
+func New(db databases.MySQLConnection) *Impl {
+	return &Impl{db: db}
+}
+
+// SaveSnapshot inserts a snapshot, unless one with the same key already exists.
+func (repo *Impl) SaveSnapshot(snapshot *entities.Snapshot) error {
+	return repo.db.GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(snapshot).Error
+}
+
+// GetContributions counts snapshots per reporter on a server since a date,
+// best contributors first. An empty serverID means every server.
+func (repo *Impl) GetContributions(serverID string, since time.Time, limit int,
+) ([]entities.Contribution, error) {
+	var contributions []entities.Contribution
+	query := repo.db.GetDB().Model(&entities.Snapshot{}).
+		Select("reporter, COUNT(*) AS contributions, MAX(reported_at) AS last_report_at").
+		Where("reporter <> '' AND reported_at >= ?", since)
+	if serverID != "" {
+		query = query.Where("server_id = ?", serverID)
+	}
+
+	response := query.Group("reporter").
+		Order("contributions DESC, last_report_at DESC").
+		Limit(limit).
+		Find(&contributions)
+	return contributions, response.Error
+}
+
+// GetStatistics computes the statistics of a server reported since a date per dimension,
+// keeping the most frequent areas and sub-areas only. An empty dimensionID means every dimension.
+// A position lives until the next one appears, the last one being left out of the average
+// lifetime; its uses are the remaining uses lost between its first and last snapshots.
+func (repo *Impl) GetStatistics(serverID, dimensionID string, since time.Time, frequencies int,
+) ([]entities.Statistics, error) {
+	args := map[string]any{"server_id": serverID, "dimension_id": dimensionID, "since": since}
+	var rows []statisticsRow
+	if err := repo.db.GetDB().Raw(statisticsQuery, args).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	areas, err := repo.getFrequencies("area_id", args, frequencies)
+	if err != nil {
+		return nil, err
+	}
+
+	subAreas, err := repo.getFrequencies("sub_area_id", args, frequencies)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := make([]entities.Statistics, 0, len(rows))
+	for _, row := range rows {
+		statistics = append(statistics, row.toStatistics(serverID, areas[row.DimensionID], subAreas[row.DimensionID]))
+	}
+
+	return statistics, nil
+}
+
+// getFrequencies returns the most frequent references per dimension, most frequent first.
+func (repo *Impl) getFrequencies(column string, args map[string]any, limit int,
+) (map[string][]entities.Frequency, error) {
+	var rows []frequencyRow
+	query := fmt.Sprintf(frequenciesQuery, column, column, column)
+	if err := repo.db.GetDB().Raw(query, args).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	frequencies := make(map[string][]entities.Frequency)
+	for _, row := range rows {
+		if len(frequencies[row.DimensionID]) < limit {
+			frequencies[row.DimensionID] = append(frequencies[row.DimensionID],
+				entities.Frequency{ID: row.ID, Positions: row.Positions})
+		}
+	}
+
+	return frequencies, nil
+}
+
+func (row statisticsRow) toStatistics(serverID string, areas, subAreas []entities.Frequency) entities.Statistics {
+	statistics := entities.Statistics{
+		ServerID:    serverID,
+		DimensionID: row.DimensionID,
+		Positions:   row.Positions,
+		Areas:       areas,
+		SubAreas:    subAreas,
+	}
+	if statistics.Areas == nil {
+		statistics.Areas = make([]entities.Frequency, 0)
+	}
+	if statistics.SubAreas == nil {
+		statistics.SubAreas = make([]entities.Frequency, 0)
+	}
+
+	if row.Positions > 0 {
+		statistics.CanopyFrequency = float64(row.Canopies) / float64(row.Positions)
+		days := max(day, row.LastReportAt.Sub(row.FirstPositionAt))
+		statistics.AverageUsesPerDay = float64(row.Uses) / (float64(days) / float64(day))
+	}
+
+	if row.Positions > 1 {
+		statistics.AverageLifetime = row.LastPositionAt.Sub(row.FirstPositionAt) / time.Duration(row.Positions-1)
+	}
+
+	return statistics
+}
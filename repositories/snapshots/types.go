@@ -0,0 +1,88 @@
+package snapshots
+
+import (
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+const (
+	day = 24 * time.Hour
+
+	// positionsQuery gathers the consecutive snapshots of a dimension sharing the same
+	// coordinates into positions, reported since @since on @server_id and on @dimension_id
+	// if not empty.
+	positionsQuery = `WITH ordered AS (
+	SELECT id, dimension_id, x, y, area_id, sub_area_id, is_in_canopy, remaining_uses, reported_at,
+		LAG(x) OVER w AS previous_x, LAG(y) OVER w AS previous_y,
+		LEAD(x) OVER w AS next_x, LEAD(y) OVER w AS next_y
+	FROM snapshots
+	WHERE server_id = @server_id AND reported_at >= @since AND (@dimension_id = '' OR dimension_id = @dimension_id)
+	WINDOW w AS (PARTITION BY dimension_id ORDER BY reported_at, id)
+), flagged AS (
+	SELECT *,
+		previous_x IS NULL OR previous_x <> x OR previous_y <> y AS is_first,
+		next_x IS NULL OR next_x <> x OR next_y <> y AS is_last
+	FROM ordered
+), numbered AS (
+	SELECT *, SUM(is_first) OVER (PARTITION BY dimension_id ORDER BY reported_at, id) AS position
+	FROM flagged
+), positions AS (
+	SELECT dimension_id,
+		MAX(IF(is_first, area_id, NULL)) AS area_id,
+		MAX(IF(is_first, sub_area_id, NULL)) AS sub_area_id,
+		MAX(IF(is_first, is_in_canopy, NULL)) AS is_in_canopy,
+		GREATEST(0, MAX(IF(is_first, remaining_uses, NULL)) - MAX(IF(is_last, remaining_uses, NULL))) AS uses,
+		MIN(reported_at) AS appeared_at,
+		MAX(reported_at) AS last_report_at
+	FROM numbered
+	GROUP BY dimension_id, position
+) `
+
+	statisticsQuery = positionsQuery + `SELECT dimension_id,
+	COUNT(*) AS positions,
+	SUM(is_in_canopy) AS canopies,
+	SUM(uses) AS uses,
+	MIN(appeared_at) AS first_position_at,
+	MAX(appeared_at) AS last_position_at,
+	MAX(last_report_at) AS last_report_at
+FROM positions
+GROUP BY dimension_id
+ORDER BY dimension_id`
+
+	// frequenciesQuery counts positions per reference, %s being the reference column.
+	frequenciesQuery = positionsQuery + `SELECT dimension_id, %s AS id, COUNT(*) AS positions
+FROM positions
+WHERE %s <> ''
+GROUP BY dimension_id, %s
+ORDER BY dimension_id, positions DESC, id`
+)
+
+type Repository interface {
+	SaveSnapshot(snapshot *entities.Snapshot) error
+	GetContributions(serverID string, since time.Time, limit int) ([]entities.Contribution, error)
+	GetStatistics(serverID, dimensionID string, since time.Time, frequencies int) ([]entities.Statistics, error)
+}
+
+type Impl struct {
+	db databases.MySQLConnection
+}
+
+// statisticsRow aggregates the positions of a dimension.
+type statisticsRow struct {
+	DimensionID     string
+	Positions       int
+	Canopies        int
+	Uses            int64
+	FirstPositionAt time.Time
+	LastPositionAt  time.Time
+	LastReportAt    time.Time
+}
+
+// frequencyRow counts the positions of a dimension found in a reference.
+type frequencyRow struct {
+	DimensionID string
+	ID          string
+	Positions   int
+}
@@ -14,3 +14,7 @@ func (repo *Impl) GetSubAreas() ([]entities.SubArea, error) {
 	response := repo.db.GetDB().Model(&entities.SubArea{}).Find(&subAreas)
 	return subAreas, response.Error
 }
+
+func (repo *Impl) SaveSubArea(subArea entities.SubArea) error {
+	return repo.db.GetDB().Save(&subArea).Error
+}
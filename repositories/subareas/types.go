@@ -7,6 +7,7 @@ import (
 
 type Repository interface {
 	GetSubAreas() ([]entities.SubArea, error)
+	SaveSubArea(subArea entities.SubArea) error
 }
 
 type Impl struct {
@@ -0,0 +1,25 @@
+package subscriptions
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+func New(db databases.MySQLConnection) *Impl {
+	return &Impl{db: db}
+}
+
+func (repo *Impl) GetSubscriptions() ([]entities.Subscription, error) {
+	var subscriptions []entities.Subscription
+	response := repo.db.GetDB().Model(&entities.Subscription{}).Find(&subscriptions)
+	return subscriptions, response.Error
+}
+
+// SaveSubscription inserts or updates a subscription; its ID is set on insertion.
+func (repo *Impl) SaveSubscription(subscription *entities.Subscription) error {
+	return repo.db.GetDB().Save(subscription).Error
+}
+
+func (repo *Impl) DeleteSubscription(id uint) error {
+	return repo.db.GetDB().Delete(&entities.Subscription{}, id).Error
+}
@@ -0,0 +1,16 @@
+package subscriptions
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/databases"
+)
+
+type Repository interface {
+	GetSubscriptions() ([]entities.Subscription, error)
+	SaveSubscription(subscription *entities.Subscription) error
+	DeleteSubscription(id uint) error
+}
+
+type Impl struct {
+	db databases.MySQLConnection
+}
@@ -14,3 +14,7 @@ func (repo *Impl) GetTransportTypes() ([]entities.TransportType, error) {
 	response := repo.db.GetDB().Model(&entities.TransportType{}).Find(&transportTypes)
 	return transportTypes, response.Error
 }
+
+func (repo *Impl) SaveTransportType(transportType entities.TransportType) error {
+	return repo.db.GetDB().Save(&transportType).Error
+}
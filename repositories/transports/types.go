@@ -7,6 +7,7 @@ import (
 
 type Repository interface {
 	GetTransportTypes() ([]entities.TransportType, error)
+	SaveTransportType(transportType entities.TransportType) error
 }
 
 type Impl struct {
@@ -0,0 +1,78 @@
+package admins
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/pollers"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+	"github.com/rs/zerolog/log"
+)
+
+func New(portalService portals.Service, pollerService pollers.Service, serverService servers.Service,
+	deadLetterService deadletters.Service, subscriptionService subscriptions.Service,
+	referenceServices ...Reloader) *Impl {
+	return &Impl{
+		portalService:       portalService,
+		pollerService:       pollerService,
+		serverService:       serverService,
+		deadLetterService:   deadLetterService,
+		subscriptionService: subscriptionService,
+		referenceServices:   referenceServices,
+	}
+}
+
+func (service *Impl) FlushCache() {
+	service.portalService.FlushCache()
+}
+
+// ReloadReferences loads every reference data again; services failing to reload keep
+// their current data, and the other ones are reloaded anyway.
+func (service *Impl) ReloadReferences() error {
+	var errs []error
+	for _, referenceService := range service.referenceServices {
+		if err := referenceService.Reload(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		log.Error().Err(err).Msgf("Cannot reload every reference data")
+		return err
+	}
+
+	log.Info().Msgf("Reference data reloaded")
+	return nil
+}
+
+// PollServer polls the portals of a known server right away; false is returned if the server is unknown.
+func (service *Impl) PollServer(ctx context.Context, serverID string) (bool, error) {
+	if _, found := service.serverService.GetServer(serverID); !found {
+		return false, nil
+	}
+
+	return true, service.pollerService.PollServer(ctx, serverID)
+}
+
+// SetSourceEnabled toggles a portal source by name; false is returned if the source is unknown.
+func (service *Impl) SetSourceEnabled(source string, enabled bool) bool {
+	if source != constants.GetDofusPortalsSource().Name {
+		return false
+	}
+
+	service.portalService.SetEnabled(enabled)
+	return true
+}
+
+func (service *Impl) GetStats() Stats {
+	return Stats{
+		Portals:       service.portalService.GetStats(),
+		DeadLetters:   len(service.deadLetterService.GetDeadLetters()),
+		Subscriptions: len(service.subscriptionService.GetSubscriptions()),
+	}
+}
@@ -0,0 +1,134 @@
+package admins
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/replies"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// NewConsumer treats the admin commands delivered on the admin queue, to commands bearing
+// the admin token only, and replies their result through RabbitMQ.
+func NewConsumer(service Service, broker amqp.MessageBroker, adminToken string) amqp.MessageConsumer {
+	impl := consumer{
+		service:    service,
+		broker:     broker,
+		adminToken: adminToken,
+	}
+
+	return impl.consume
+}
+
+func (consumer *consumer) consume(ctx amqp.Context, message *amqp.RabbitMQMessage) {
+	command, err := readCommand(message)
+	if err == nil && subtle.ConstantTimeCompare([]byte(command.Token), []byte(consumer.adminToken)) != 1 {
+		err = errUnauthenticated
+	}
+	if err != nil {
+		log.Warn().Err(err).
+			Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Msgf("Cannot treat admin command, rejected")
+		consumer.reply(ctx, message, answer{Error: err.Error()})
+		return
+	}
+
+	log.Info().
+		Str(constants.LogCorrelationID, ctx.CorrelationID).
+		Str(constants.LogCommand, command.Name).
+		Msgf("Running admin command")
+	consumer.reply(ctx, message, consumer.run(ctx, command))
+}
+
+func (consumer *consumer) run(ctx context.Context, command command) answer {
+	switch command.Name {
+	case constants.AdminCommandFlushCache:
+		consumer.service.FlushCache()
+	case constants.AdminCommandReloadReferences:
+		if err := consumer.service.ReloadReferences(); err != nil {
+			return answer{Error: err.Error()}
+		}
+	case constants.AdminCommandPollServer:
+		found, err := consumer.service.PollServer(ctx, command.ServerID)
+		if !found {
+			return answer{Error: fmt.Sprintf("%v: %q", errUnknownServer, command.ServerID)}
+		}
+		if err != nil {
+			return answer{Error: err.Error()}
+		}
+	case constants.AdminCommandToggleSource:
+		if !consumer.service.SetSourceEnabled(command.Source, command.Enabled) {
+			return answer{Error: fmt.Sprintf("%v: %q", errUnknownSource, command.Source)}
+		}
+	case constants.AdminCommandStats:
+		stats := consumer.service.GetStats()
+		return answer{Stats: &stats}
+	default:
+		return answer{Error: fmt.Sprintf("%v: %q", errUnknownCommand, command.Name)}
+	}
+
+	return answer{}
+}
+
+func (consumer *consumer) reply(ctx amqp.Context, request *amqp.RabbitMQMessage, answer answer) {
+	message := amqp.RabbitMQMessage{
+		Type:     request.Type,
+		Status:   amqp.RabbitMQMessage_SUCCESS,
+		Language: request.Language,
+	}
+	if answer.Error != "" {
+		message.Status = amqp.RabbitMQMessage_FAILED
+	}
+
+	payload, err := json.Marshal(answer)
+	if err != nil {
+		log.Error().Err(err).
+			Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Msgf("Cannot encode admin answer, replying without it")
+	} else {
+		message.ProtoReflect().SetUnknown(protowire.AppendBytes(
+			protowire.AppendTag(nil, constants.AdminPayloadField, protowire.BytesType), payload))
+	}
+
+	replies.Answer(ctx, consumer.broker, &message)
+}
+
+// readCommand decodes the admin command held by the unknown fields of the message.
+func readCommand(message *amqp.RabbitMQMessage) (command, error) {
+	fields := message.ProtoReflect().GetUnknown()
+	for len(fields) > 0 {
+		number, fieldType, length := protowire.ConsumeTag(fields)
+		if length < 0 {
+			return command{}, fmt.Errorf("%w: %w", errInvalidCommand, protowire.ParseError(length))
+		}
+		fields = fields[length:]
+
+		if number != constants.AdminPayloadField || fieldType != protowire.BytesType {
+			length = protowire.ConsumeFieldValue(number, fieldType, fields)
+			if length < 0 {
+				return command{}, fmt.Errorf("%w: %w", errInvalidCommand, protowire.ParseError(length))
+			}
+			fields = fields[length:]
+			continue
+		}
+
+		payload, length := protowire.ConsumeBytes(fields)
+		if length < 0 {
+			return command{}, fmt.Errorf("%w: %w", errInvalidCommand, protowire.ParseError(length))
+		}
+
+		var result command
+		if err := json.Unmarshal(payload, &result); err != nil {
+			return command{}, fmt.Errorf("%w: %w", errInvalidCommand, err)
+		}
+
+		return result, nil
+	}
+
+	return command{}, errMissingCommand
+}
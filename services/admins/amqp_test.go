@@ -0,0 +1,129 @@
+package admins
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mockbrokers "github.com/kaellybot/kaelly-portals/mocks/brokers"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func adminMessage(t *testing.T, command command) *amqp.RabbitMQMessage {
+	t.Helper()
+	payload, err := json.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sent over the wire and read back, as the broker would.
+	message := &amqp.RabbitMQMessage{}
+	message.ProtoReflect().SetUnknown(protowire.AppendBytes(
+		protowire.AppendTag(nil, constants.AdminPayloadField, protowire.BytesType), payload))
+	body, err := proto.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := &amqp.RabbitMQMessage{}
+	if err = proto.Unmarshal(body, received); err != nil {
+		t.Fatal(err)
+	}
+
+	return received
+}
+
+func TestAdminConsumer(t *testing.T) {
+	tests := []struct {
+		name           string
+		message        func(t *testing.T) *amqp.RabbitMQMessage
+		expectedStatus amqp.RabbitMQMessage_Status
+		expectedStats  bool
+	}{
+		{name: "missing command", message: func(_ *testing.T) *amqp.RabbitMQMessage {
+			return &amqp.RabbitMQMessage{}
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "missing token", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Name: constants.AdminCommandFlushCache})
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "wrong token", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: "wrong", Name: constants.AdminCommandFlushCache})
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "unknown command", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: "unknown"})
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "flush", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandFlushCache})
+		}, expectedStatus: amqp.RabbitMQMessage_SUCCESS},
+		{name: "reload", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandReloadReferences})
+		}, expectedStatus: amqp.RabbitMQMessage_SUCCESS},
+		{name: "poll", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandPollServer,
+				ServerID: "known"})
+		}, expectedStatus: amqp.RabbitMQMessage_SUCCESS},
+		{name: "poll unknown server", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandPollServer,
+				ServerID: "unknown"})
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "poll failure", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandPollServer,
+				ServerID: "failing"})
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "disable source", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandToggleSource,
+				Source: constants.GetDofusPortalsSource().Name})
+		}, expectedStatus: amqp.RabbitMQMessage_SUCCESS},
+		{name: "enable unknown source", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandToggleSource,
+				Source: "unknown", Enabled: true})
+		}, expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "stats", message: func(t *testing.T) *amqp.RabbitMQMessage {
+			return adminMessage(t, command{Token: adminToken, Name: constants.AdminCommandStats})
+		}, expectedStatus: amqp.RabbitMQMessage_SUCCESS, expectedStats: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			broker := mockbrokers.New()
+			consume := NewConsumer(&fakeAdmin{}, broker, adminToken)
+			consume(amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "admin"},
+				test.message(t))
+
+			replies := broker.Replies()
+			if len(replies) != 1 || replies[0].CorrelationID != "correlation" || replies[0].ReplyTo != "admin" {
+				t.Fatalf("expected a reply to the admin command, got %v", replies)
+			}
+			reply := replies[0].Message
+			if reply.Status != test.expectedStatus {
+				t.Errorf("expected status %v, got %v", test.expectedStatus, reply.Status)
+			}
+
+			answer := readAnswer(t, reply)
+			if (answer.Stats != nil) != test.expectedStats {
+				t.Errorf("expected stats %v, got %v", test.expectedStats, answer.Stats)
+			}
+			if (answer.Error != "") != (test.expectedStatus == amqp.RabbitMQMessage_FAILED) {
+				t.Errorf("unexpected error %q for status %v", answer.Error, reply.Status)
+			}
+		})
+	}
+}
+
+func readAnswer(t *testing.T, message *amqp.RabbitMQMessage) answer {
+	t.Helper()
+	number, fieldType, length := protowire.ConsumeTag(message.ProtoReflect().GetUnknown())
+	if length < 0 || number != constants.AdminPayloadField || fieldType != protowire.BytesType {
+		t.Fatal("answer does not carry the admin payload")
+	}
+	payload, _ := protowire.ConsumeBytes(message.ProtoReflect().GetUnknown()[length:])
+
+	var result answer
+	if err := json.Unmarshal(payload, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
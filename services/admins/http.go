@@ -0,0 +1,87 @@
+package admins
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/rs/zerolog/log"
+)
+
+// NewHandler serves the admin commands over HTTP, to requests bearing the admin token only.
+func NewHandler(service Service, adminToken string) http.Handler {
+	impl := handler{
+		service:    service,
+		adminToken: adminToken,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/admin/cache/flush", impl.authenticate(impl.flushCache))
+	mux.HandleFunc("POST /v1/admin/references/reload", impl.authenticate(impl.reloadReferences))
+	mux.HandleFunc("POST /v1/admin/servers/{serverID}/poll", impl.authenticate(impl.pollServer))
+	mux.HandleFunc("POST /v1/admin/sources/{source}/enable", impl.authenticate(impl.toggleSource(true)))
+	mux.HandleFunc("POST /v1/admin/sources/{source}/disable", impl.authenticate(impl.toggleSource(false)))
+	mux.HandleFunc("GET /v1/admin/stats", impl.authenticate(impl.stats))
+
+	return mux
+}
+
+// authenticate only lets through requests bearing the admin token.
+func (handler *handler) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix)
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(handler.adminToken)) != 1 {
+			log.Warn().Str(constants.LogRoute, r.URL.Path).Msgf("Unauthenticated admin command, rejected")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		log.Info().Str(constants.LogRoute, r.URL.Path).Msgf("Running admin command")
+		next(w, r)
+	}
+}
+
+func (handler *handler) flushCache(w http.ResponseWriter, _ *http.Request) {
+	handler.service.FlushCache()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *handler) reloadReferences(w http.ResponseWriter, _ *http.Request) {
+	if err := handler.service.ReloadReferences(); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *handler) pollServer(w http.ResponseWriter, r *http.Request) {
+	found, err := handler.service.PollServer(r.Context(), r.PathValue("serverID"))
+	switch {
+	case !found:
+		w.WriteHeader(http.StatusNotFound)
+	case err != nil:
+		w.WriteHeader(http.StatusBadGateway)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (handler *handler) toggleSource(enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !handler.service.SetSourceEnabled(r.PathValue("source"), enabled) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (handler *handler) stats(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(handler.service.GetStats()); err != nil {
+		log.Error().Err(err).Msgf("Cannot write HTTP response")
+	}
+}
@@ -0,0 +1,99 @@
+package admins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+)
+
+const adminToken = "secret"
+
+var errPoll = errors.New("poll failed")
+
+type fakeAdmin struct {
+	flushed bool
+}
+
+func (fake *fakeAdmin) FlushCache() {
+	fake.flushed = true
+}
+
+func (fake *fakeAdmin) ReloadReferences() error {
+	return nil
+}
+
+func (fake *fakeAdmin) PollServer(_ context.Context, serverID string) (bool, error) {
+	switch serverID {
+	case "known":
+		return true, nil
+	case "failing":
+		return true, errPoll
+	default:
+		return false, nil
+	}
+}
+
+func (fake *fakeAdmin) SetSourceEnabled(source string, _ bool) bool {
+	return source == constants.GetDofusPortalsSource().Name
+}
+
+func (fake *fakeAdmin) GetStats() Stats {
+	return Stats{}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "missing token", method: http.MethodPost, path: "/v1/admin/cache/flush",
+			expectedStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, path: "/v1/admin/cache/flush", token: "wrong",
+			expectedStatus: http.StatusUnauthorized},
+		{name: "flush", method: http.MethodPost, path: "/v1/admin/cache/flush", token: adminToken,
+			expectedStatus: http.StatusNoContent},
+		{name: "reload", method: http.MethodPost, path: "/v1/admin/references/reload", token: adminToken,
+			expectedStatus: http.StatusNoContent},
+		{name: "poll", method: http.MethodPost, path: "/v1/admin/servers/known/poll", token: adminToken,
+			expectedStatus: http.StatusNoContent},
+		{name: "poll unknown server", method: http.MethodPost, path: "/v1/admin/servers/unknown/poll",
+			token: adminToken, expectedStatus: http.StatusNotFound},
+		{name: "poll failure", method: http.MethodPost, path: "/v1/admin/servers/failing/poll",
+			token: adminToken, expectedStatus: http.StatusBadGateway},
+		{name: "disable source", method: http.MethodPost, path: "/v1/admin/sources/dofus-portals.fr/disable",
+			token: adminToken, expectedStatus: http.StatusNoContent},
+		{name: "enable unknown source", method: http.MethodPost, path: "/v1/admin/sources/unknown/enable",
+			token: adminToken, expectedStatus: http.StatusNotFound},
+		{name: "stats", method: http.MethodGet, path: "/v1/admin/stats", token: adminToken,
+			expectedStatus: http.StatusOK},
+	}
+
+	admin := &fakeAdmin{}
+	handler := NewHandler(admin, adminToken)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, test.path, nil)
+			if test.token != "" {
+				request.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("expected status %v, got %v", test.expectedStatus, recorder.Code)
+			}
+		})
+	}
+
+	if !admin.flushed {
+		t.Error("cache not flushed")
+	}
+}
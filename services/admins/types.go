@@ -0,0 +1,84 @@
+package admins
+
+import (
+	"context"
+	"errors"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/pollers"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+)
+
+const bearerPrefix = "Bearer "
+
+var (
+	errMissingCommand  = errors.New("message does not carry any admin command")
+	errInvalidCommand  = errors.New("admin command cannot be read")
+	errUnauthenticated = errors.New("admin command does not bear the admin token")
+	errUnknownCommand  = errors.New("unknown admin command")
+	errUnknownServer   = errors.New("unknown server")
+	errUnknownSource   = errors.New("unknown source")
+)
+
+type Service interface {
+	FlushCache()
+	ReloadReferences() error
+	// PollServer returns false if the server is unknown.
+	PollServer(ctx context.Context, serverID string) (bool, error)
+	// SetSourceEnabled returns false if the source is unknown.
+	SetSourceEnabled(source string, enabled bool) bool
+	GetStats() Stats
+}
+
+// Reloader is a service holding reference data, loaded again on demand.
+type Reloader interface {
+	Reload() error
+}
+
+// Impl runs operator commands against the other services.
+type Impl struct {
+	portalService       portals.Service
+	pollerService       pollers.Service
+	serverService       servers.Service
+	deadLetterService   deadletters.Service
+	subscriptionService subscriptions.Service
+	referenceServices   []Reloader
+}
+
+// handler serves the admin commands over HTTP.
+type handler struct {
+	service    Service
+	adminToken string
+}
+
+// consumer treats the admin commands delivered on the admin queue.
+type consumer struct {
+	service    Service
+	broker     amqp.MessageBroker
+	adminToken string
+}
+
+// command is an admin command, JSON-encoded in the admin payload field of a RabbitMQ message.
+type command struct {
+	Token    string `json:"token"`
+	Name     string `json:"command"`
+	ServerID string `json:"serverId,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Enabled  bool   `json:"enabled,omitempty"`
+}
+
+// answer is the result of an admin command, replied in the admin payload field.
+type answer struct {
+	Stats *Stats `json:"stats,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Stats gathers the internal state of the application.
+type Stats struct {
+	Portals       portals.Stats `json:"portals"`
+	DeadLetters   int           `json:"deadLetters"`
+	Subscriptions int           `json:"subscriptions"`
+}
@@ -6,23 +6,38 @@ import (
 )
 
 func New(areaRepo areas.Repository) (*Impl, error) {
-	areaEntities, err := areaRepo.GetAreas()
-	if err != nil {
+	service := Impl{
+		areaRepo: areaRepo,
+	}
+
+	if err := service.Reload(); err != nil {
 		return nil, err
 	}
 
+	return &service, nil
+}
+
+// Reload loads areas again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	areaEntities, err := service.areaRepo.GetAreas()
+	if err != nil {
+		return err
+	}
+
 	areas := make(map[string]entities.Area)
 	for _, area := range areaEntities {
 		areas[area.DofusPortalsID] = area
 	}
 
-	return &Impl{
-		areas:    areas,
-		areaRepo: areaRepo,
-	}, nil
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.areas = areas
+	return nil
 }
 
 func (service *Impl) FindAreaByDofusPortalsID(dofusPortalsID string) (entities.Area, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	area, found := service.areas[dofusPortalsID]
 	return area, found
 }
@@ -1,15 +1,19 @@
 package areas
 
 import (
+	"sync"
+
 	"github.com/kaellybot/kaelly-portals/models/entities"
 	"github.com/kaellybot/kaelly-portals/repositories/areas"
 )
 
 type Service interface {
 	FindAreaByDofusPortalsID(dofusPortalsID string) (entities.Area, bool)
+	Reload() error
 }
 
 type Impl struct {
+	mutex    sync.RWMutex
 	areas    map[string]entities.Area
 	areaRepo areas.Repository
 }
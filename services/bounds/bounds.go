@@ -0,0 +1,112 @@
+package bounds
+
+import (
+	"fmt"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/bounds"
+)
+
+func New(mapBoundsRepo bounds.Repository) (*Impl, error) {
+	service := Impl{
+		mapBoundsRepo: mapBoundsRepo,
+	}
+
+	if err := service.Reload(); err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+// Reload loads map bounds again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	mapBounds, err := service.mapBoundsRepo.GetMapBounds()
+	if err != nil {
+		return err
+	}
+
+	subAreaBounds := make(map[string][]entities.MapBounds)
+	subAreaAreas := make(map[string]string)
+	for _, bound := range mapBounds {
+		if bound.SubAreaID != "" {
+			subAreaBounds[bound.SubAreaID] = append(subAreaBounds[bound.SubAreaID], bound)
+			subAreaAreas[bound.SubAreaID] = bound.AreaID
+		}
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.mapBounds = mapBounds
+	service.subAreaBounds = subAreaBounds
+	service.subAreaAreas = subAreaAreas
+	return nil
+}
+
+// Validate returns an error if the position, or one of its transports, is suspicious.
+func (service *Impl) Validate(position *amqp.PortalPositionAnswer_PortalPosition) error {
+	if position.GetPosition() == nil {
+		return nil
+	}
+
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	if err := service.validateCoordinates(position.GetPosition().GetX(), position.GetPosition().GetY()); err != nil {
+		return err
+	}
+
+	if err := service.validateTransport(position.GetPosition().GetTransport()); err != nil {
+		return err
+	}
+
+	return service.validateTransport(position.GetPosition().GetConditionalTransport())
+}
+
+// ValidateCoordinates returns an error if no known map bounds contain the coordinates.
+func (service *Impl) ValidateCoordinates(x, y int64) error {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	return service.validateCoordinates(x, y)
+}
+
+func (service *Impl) validateCoordinates(x, y int64) error {
+	if len(service.mapBounds) == 0 || contains(service.mapBounds, x, y) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: [%d,%d]", errImpossibleCoordinates, x, y)
+}
+
+func (service *Impl) validateTransport(transport *amqp.PortalPositionAnswer_PortalPosition_Position_Transport,
+) error {
+	if transport == nil {
+		return nil
+	}
+
+	subAreaBounds, found := service.subAreaBounds[transport.GetSubAreaId()]
+	if !found {
+		return nil
+	}
+
+	if areaID := service.subAreaAreas[transport.GetSubAreaId()]; areaID != "" && areaID != transport.GetAreaId() {
+		return fmt.Errorf("%w: %s is not in %s", errAreaMismatch, transport.GetSubAreaId(), transport.GetAreaId())
+	}
+
+	if !contains(subAreaBounds, transport.GetX(), transport.GetY()) {
+		return fmt.Errorf("%w: [%d,%d] is not in %s", errTransportMismatch,
+			transport.GetX(), transport.GetY(), transport.GetSubAreaId())
+	}
+
+	return nil
+}
+
+func contains(mapBounds []entities.MapBounds, x, y int64) bool {
+	for _, bound := range mapBounds {
+		if x >= bound.MinX && x <= bound.MaxX && y >= bound.MinY && y <= bound.MaxY {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package bounds
+
+import (
+	"errors"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+)
+
+func newPosition(x, y int64,
+	transport *amqp.PortalPositionAnswer_PortalPosition_Position_Transport,
+) *amqp.PortalPositionAnswer_PortalPosition {
+	return &amqp.PortalPositionAnswer_PortalPosition{
+		Position: &amqp.PortalPositionAnswer_PortalPosition_Position{X: x, Y: y, Transport: transport},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	service, err := New(references.MapBounds{
+		{AreaID: "astrub", MinX: -10, MaxX: 10, MinY: -30, MaxY: -10},
+		{AreaID: "astrub", SubAreaID: "astrub-city", MinX: 0, MaxX: 10, MinY: -25, MaxY: -15},
+		{AreaID: "astrub", SubAreaID: "astrub-city", MinX: -2, MaxX: 0, MinY: -20, MaxY: -18},
+		{AreaID: "amakna", MinX: -5, MaxX: 15, MinY: -10, MaxY: 30},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		position      *amqp.PortalPositionAnswer_PortalPosition
+		expectedError error
+	}{
+		{
+			name:     "valid position",
+			position: newPosition(5, -18, transport("astrub", "astrub-city", 5, -18)),
+		},
+		{
+			name:     "transport in second sub-area bounds",
+			position: newPosition(5, -18, transport("astrub", "astrub-city", -1, -19)),
+		},
+		{
+			name:     "transport sub-area without bounds",
+			position: newPosition(5, -18, transport("astrub", "astrub-forest", 100, 100)),
+		},
+		{
+			name:          "impossible coordinates",
+			position:      newPosition(-999, 12, nil),
+			expectedError: errImpossibleCoordinates,
+		},
+		{
+			name:          "transport out of its sub-area",
+			position:      newPosition(5, -18, transport("astrub", "astrub-city", 5, 0)),
+			expectedError: errTransportMismatch,
+		},
+		{
+			name:          "sub-area of another area",
+			position:      newPosition(5, -18, transport("amakna", "astrub-city", 5, -18)),
+			expectedError: errAreaMismatch,
+		},
+		{
+			name: "conditional transport out of its sub-area",
+			position: &amqp.PortalPositionAnswer_PortalPosition{
+				Position: &amqp.PortalPositionAnswer_PortalPosition_Position{X: 5, Y: -18,
+					ConditionalTransport: transport("astrub", "astrub-city", 5, 0)},
+			},
+			expectedError: errTransportMismatch,
+		},
+		{
+			name:     "unknown position",
+			position: &amqp.PortalPositionAnswer_PortalPosition{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := service.Validate(test.position); !errors.Is(err, test.expectedError) {
+				t.Errorf("expected %v, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateWithoutBounds(t *testing.T) {
+	service, err := New(references.MapBounds{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = service.Validate(newPosition(-999, 12, transport("astrub", "astrub-city", 5, 0))); err != nil {
+		t.Errorf("expected no validation without bounds, got %v", err)
+	}
+}
+
+func transport(areaID, subAreaID string, x, y int64,
+) *amqp.PortalPositionAnswer_PortalPosition_Position_Transport {
+	return &amqp.PortalPositionAnswer_PortalPosition_Position_Transport{
+		AreaId:    areaID,
+		SubAreaId: subAreaID,
+		X:         x,
+		Y:         y,
+	}
+}
@@ -0,0 +1,32 @@
+package bounds
+
+import (
+	"errors"
+	"sync"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/bounds"
+)
+
+var (
+	errImpossibleCoordinates = errors.New("coordinates outside of every known map bounds")
+	errTransportMismatch     = errors.New("transport outside of its sub-area bounds")
+	errAreaMismatch          = errors.New("sub-area does not belong to the transport area")
+)
+
+type Service interface {
+	Validate(position *amqp.PortalPositionAnswer_PortalPosition) error
+	ValidateCoordinates(x, y int64) error
+	Reload() error
+}
+
+// Impl checks positions against the map bounds dataset; without bounds
+// for an area or a sub-area, the related checks are skipped.
+type Impl struct {
+	mutex         sync.RWMutex
+	mapBounds     []entities.MapBounds
+	subAreaBounds map[string][]entities.MapBounds
+	subAreaAreas  map[string]string
+	mapBoundsRepo bounds.Repository
+}
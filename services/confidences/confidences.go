@@ -0,0 +1,156 @@
+package confidences
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+)
+
+func New(maxAge time.Duration, boundsService bounds.Service) *Impl {
+	return &Impl{
+		maxAge:        maxAge,
+		now:           time.Now,
+		reporters:     make(map[string]map[string]time.Time),
+		boundsService: boundsService,
+	}
+}
+
+// Observe records the reports behind positions to build the reporter history, forgetting
+// the expired ones, and updates the number of outdated positions per server and dimension.
+func (service *Impl) Observe(positions []*amqp.PortalPositionAnswer_PortalPosition) {
+	now := service.now()
+	service.mutex.Lock()
+	for _, position := range positions {
+		reporter := getReporter(position)
+		reportedAt, found := getReportedAt(position)
+		if reporter == "" || position.GetPosition() == nil || !found || now.Sub(reportedAt) > service.maxAge {
+			continue
+		}
+
+		reports, found := service.reporters[reporter]
+		if !found {
+			reports = make(map[string]time.Time)
+			service.reporters[reporter] = reports
+		}
+		reports[getReportKey(position)] = reportedAt
+	}
+	service.prune(now)
+	service.mutex.Unlock()
+
+	for key, count := range service.countOutdated(positions) {
+		insights.OutdatedPositions.WithLabelValues(key[0], key[1]).Set(float64(count))
+	}
+}
+
+// countOutdated returns the number of outdated positions per server and dimension,
+// including the ones having none.
+func (service *Impl) countOutdated(positions []*amqp.PortalPositionAnswer_PortalPosition) map[[2]string]int {
+	outdated := make(map[[2]string]int)
+	for _, position := range positions {
+		if position.GetPosition() == nil {
+			continue
+		}
+
+		key := [2]string{position.GetServerId(), position.GetDimensionId()}
+		count := outdated[key]
+		if service.Score(position).Outdated {
+			count++
+		}
+		outdated[key] = count
+	}
+
+	return outdated
+}
+
+// prune forgets the reports older than maxAge, and the reporters left without report.
+func (service *Impl) prune(now time.Time) {
+	for reporter, reports := range service.reporters {
+		for key, reportedAt := range reports {
+			if now.Sub(reportedAt) > service.maxAge {
+				delete(reports, key)
+			}
+		}
+
+		if len(reports) == 0 {
+			delete(service.reporters, reporter)
+		}
+	}
+}
+
+func (service *Impl) Score(position *amqp.PortalPositionAnswer_PortalPosition) Confidence {
+	if position.GetPosition() == nil {
+		return Confidence{}
+	}
+
+	reportedAt, found := getReportedAt(position)
+	ageFactor := 0.0
+	outdated := false
+	if found {
+		age := service.now().Sub(reportedAt)
+		outdated = age > service.maxAge
+		ageFactor = math.Max(0, 1-age.Seconds()/service.maxAge.Seconds())
+	}
+
+	score := ageWeight*math.Min(1, ageFactor) +
+		usesWeight*getUsesFactor(position.GetRemainingUses()) +
+		reporterWeight*service.getReporterFactor(getReporter(position))
+
+	if service.boundsService.Validate(position) != nil {
+		return Confidence{Outdated: outdated, Suspicious: true}
+	}
+
+	return Confidence{
+		Score:    math.Round(score*100) / 100,
+		Outdated: outdated,
+	}
+}
+
+func (service *Impl) getReporterFactor(reporter string) float64 {
+	if reporter == "" {
+		return neutralFactor
+	}
+
+	service.mutex.RLock()
+	reports := float64(len(service.reporters[reporter]))
+	service.mutex.RUnlock()
+	return reports / (reports + reporterTrustThreshold)
+}
+
+// getUsesFactor considers unknown remaining uses, reported as 0, as neutral.
+func getUsesFactor(remainingUses int64) float64 {
+	if remainingUses <= 0 {
+		return neutralFactor
+	}
+
+	return math.Min(1, float64(remainingUses)/fullRemainingUses)
+}
+
+func getReporter(position *amqp.PortalPositionAnswer_PortalPosition) string {
+	if position.GetUpdatedBy() != "" {
+		return position.GetUpdatedBy()
+	}
+
+	return position.GetCreatedBy()
+}
+
+func getReportedAt(position *amqp.PortalPositionAnswer_PortalPosition) (time.Time, bool) {
+	if position.GetUpdatedAt() != nil {
+		return position.GetUpdatedAt().AsTime(), true
+	}
+
+	if position.GetCreatedAt() != nil {
+		return position.GetCreatedAt().AsTime(), true
+	}
+
+	return time.Time{}, false
+}
+
+func getReportKey(position *amqp.PortalPositionAnswer_PortalPosition) string {
+	reportedAt, _ := getReportedAt(position)
+	return fmt.Sprintf("%s/%s/%d/%d/%d", position.GetServerId(), position.GetDimensionId(),
+		position.GetPosition().GetX(), position.GetPosition().GetY(), reportedAt.Unix())
+}
@@ -0,0 +1,142 @@
+package confidences
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//nolint:gochecknoglobals // Fixed clock shared by tests.
+var now = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestService() *Impl {
+	boundsService, _ := bounds.New(references.MapBounds{
+		entities.MapBounds{AreaID: "astrub", MinX: -10, MaxX: 10, MinY: -10, MaxY: 10},
+	})
+	service := New(24*time.Hour, boundsService)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func newPosition(reporter string, age time.Duration, remainingUses int64) *amqp.PortalPositionAnswer_PortalPosition {
+	return &amqp.PortalPositionAnswer_PortalPosition{
+		ServerId:      "imagiro",
+		DimensionId:   "enutrosor",
+		RemainingUses: remainingUses,
+		UpdatedBy:     reporter,
+		UpdatedAt:     timestamppb.New(now.Add(-age)),
+		Position:      &amqp.PortalPositionAnswer_PortalPosition_Position{X: 1, Y: 2},
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		position *amqp.PortalPositionAnswer_PortalPosition
+		expected Confidence
+	}{
+		{
+			name:     "fresh anonymous position",
+			position: newPosition("", 0, fullRemainingUses),
+			expected: Confidence{Score: 0.88},
+		},
+		{
+			name:     "half max age and unknown uses",
+			position: newPosition("", 12*time.Hour, 0),
+			expected: Confidence{Score: 0.5},
+		},
+		{
+			name:     "older than max age",
+			position: newPosition("", 6*24*time.Hour, 10),
+			expected: Confidence{Score: 0.15, Outdated: true},
+		},
+		{
+			name: "no timestamp",
+			position: &amqp.PortalPositionAnswer_PortalPosition{
+				RemainingUses: fullRemainingUses,
+				Position:      &amqp.PortalPositionAnswer_PortalPosition_Position{},
+			},
+			expected: Confidence{Score: 0.38},
+		},
+		{
+			name: "suspicious position",
+			position: &amqp.PortalPositionAnswer_PortalPosition{
+				UpdatedAt:     timestamppb.New(now.Add(-25 * time.Hour)),
+				RemainingUses: fullRemainingUses,
+				Position:      &amqp.PortalPositionAnswer_PortalPosition_Position{X: -999, Y: 12},
+			},
+			expected: Confidence{Outdated: true, Suspicious: true},
+		},
+		{
+			name:     "unknown position",
+			position: &amqp.PortalPositionAnswer_PortalPosition{},
+			expected: Confidence{},
+		},
+	}
+
+	service := newTestService()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := service.Score(test.position); result != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestReporterHistory(t *testing.T) {
+	service := newTestService()
+	position := newPosition("reporter", 0, fullRemainingUses)
+	if score := service.Score(position).Score; score != 0.75 {
+		t.Errorf("unknown reporter: expected 0.75, got %v", score)
+	}
+
+	// The same report seen several times only counts once.
+	service.Observe([]*amqp.PortalPositionAnswer_PortalPosition{position, position})
+	first := service.Score(position).Score
+
+	for i := range 9 {
+		service.Observe([]*amqp.PortalPositionAnswer_PortalPosition{
+			newPosition("reporter", time.Duration(i+1)*time.Minute, fullRemainingUses),
+		})
+	}
+	if score := service.Score(position).Score; score <= first || score != 0.92 {
+		t.Errorf("expected score to grow with reports from %v to 0.92, got %v", first, score)
+	}
+}
+
+func TestReporterHistoryExpiration(t *testing.T) {
+	service := newTestService()
+	service.Observe([]*amqp.PortalPositionAnswer_PortalPosition{
+		newPosition("reporter", time.Hour, fullRemainingUses),
+		newPosition("expired", 25*time.Hour, fullRemainingUses),
+	})
+	if _, found := service.reporters["expired"]; found {
+		t.Error("expired report recorded")
+	}
+
+	service.now = func() time.Time { return now.Add(24 * time.Hour) }
+	service.Observe(nil)
+	if len(service.reporters) != 0 {
+		t.Errorf("expired reports kept: %v", service.reporters)
+	}
+}
+
+func TestOutdatedPositions(t *testing.T) {
+	service := newTestService()
+	outdated := newPosition("reporter", 25*time.Hour, fullRemainingUses)
+	fresh := newPosition("reporter", 0, 0)
+	fresh.DimensionId = "srambad"
+
+	counts := service.countOutdated([]*amqp.PortalPositionAnswer_PortalPosition{outdated, fresh})
+	expected := map[[2]string]int{{"imagiro", "enutrosor"}: 1, {"imagiro", "srambad"}: 0}
+	if !maps.Equal(counts, expected) {
+		t.Errorf("expected %v, got %v", expected, counts)
+	}
+}
@@ -0,0 +1,50 @@
+package confidences
+
+import (
+	"sync"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+)
+
+const (
+	ageWeight      = 0.5
+	usesWeight     = 0.25
+	reporterWeight = 0.25
+
+	// neutralFactor is used when a criterion cannot be evaluated.
+	neutralFactor = 0.5
+
+	// fullRemainingUses is the number of remaining uses above which
+	// a position gets the best uses factor.
+	fullRemainingUses = 100
+
+	// reporterTrustThreshold is the number of reports at which a reporter
+	// gets half of the best reporter factor.
+	reporterTrustThreshold = 5
+)
+
+type Service interface {
+	Observe(positions []*amqp.PortalPositionAnswer_PortalPosition)
+	Score(position *amqp.PortalPositionAnswer_PortalPosition) Confidence
+}
+
+// Confidence of a portal position, from 0 (unreliable) to 1 (fresh and reliable).
+// Outdated is set when the position is older than the configured max age, and
+// Suspicious when it fails map bounds validation, its score being then 0.
+type Confidence struct {
+	Score      float64 `json:"score"`
+	Outdated   bool    `json:"outdated"`
+	Suspicious bool    `json:"suspicious"`
+}
+
+// Impl scores positions from their age, their remaining uses and the number
+// of distinct reports seen from their reporter that are not older than maxAge.
+type Impl struct {
+	maxAge        time.Duration
+	now           func() time.Time
+	mutex         sync.RWMutex
+	reporters     map[string]map[string]time.Time
+	boundsService bounds.Service
+}
@@ -0,0 +1,207 @@
+package deadletters
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/deadletters"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// New purges outdated dead letters before loading the remaining ones.
+func New(config configs.Config, deadLetterRepo deadletters.Repository) (*Impl, error) {
+	service := Impl{
+		maxAttempts:    config.DeadLetterMaxAttempts,
+		retention:      config.DeadLetterRetention,
+		maxEntries:     config.DeadLetterMaxEntries,
+		deadLetters:    make(map[uint]*entities.DeadLetter),
+		correlations:   make(map[string]uint),
+		deadLetterRepo: deadLetterRepo,
+	}
+	if err := service.Reload(); err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+// Reload purges outdated dead letters and loads the remaining ones again, in place of
+// the ones known so far; used when MySQL is reachable again after starting without it.
+func (service *Impl) Reload() error {
+	service.purge()
+
+	deadLetterEntities, err := service.deadLetterRepo.GetDeadLetters()
+	if err != nil {
+		return err
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.deadLetters = make(map[uint]*entities.DeadLetter)
+	service.correlations = make(map[string]uint)
+	for _, deadLetter := range deadLetterEntities {
+		service.track(&deadLetter)
+	}
+	service.evict()
+	service.updateGauge()
+	return nil
+}
+
+// Record keeps the message with the error it caused. A poison message is quarantined
+// straight away; otherwise, it is once it failed the maximum number of attempts.
+func (service *Impl) Record(ctx amqp.Context, message *amqp.RabbitMQMessage, cause error, poison bool) {
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Msgf("Cannot encode dead letter, keeping it without payload")
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	deadLetter := &entities.DeadLetter{
+		CorrelationID: ctx.CorrelationID,
+		ReplyTo:       ctx.ReplyTo,
+		RoutingKey:    ctx.RoutingKey,
+	}
+	if id, found := service.correlations[ctx.CorrelationID]; found && ctx.CorrelationID != "" {
+		copied := *service.deadLetters[id]
+		deadLetter = &copied
+	}
+
+	deadLetter.Payload = payload
+	deadLetter.Error = cause.Error()
+	deadLetter.Attempts++
+	deadLetter.Quarantined = poison || deadLetter.Attempts >= service.maxAttempts
+
+	if err = service.deadLetterRepo.SaveDeadLetter(deadLetter); err != nil {
+		log.Error().Err(err).Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Msgf("Cannot save dead letter, request not recorded")
+		return
+	}
+
+	service.track(deadLetter)
+	service.evict()
+	service.updateGauge()
+
+	status := statusFailing
+	if deadLetter.Quarantined {
+		status = statusQuarantined
+	}
+	insights.DeadLetters.WithLabelValues(status).Inc()
+	log.Warn().
+		Str(constants.LogCorrelationID, ctx.CorrelationID).
+		Int(constants.LogAttempts, deadLetter.Attempts).
+		Bool(constants.LogQuarantined, deadLetter.Quarantined).
+		Msgf("Request recorded as dead letter")
+}
+
+// Resolve forgets a correlation ID previously recorded, once its request succeeded.
+func (service *Impl) Resolve(correlationID string) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	id, found := service.correlations[correlationID]
+	if !found || correlationID == "" {
+		return
+	}
+
+	service.forget(id)
+}
+
+func (service *Impl) IsQuarantined(correlationID string) bool {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	id, found := service.correlations[correlationID]
+	return found && correlationID != "" && service.deadLetters[id].Quarantined
+}
+
+// GetDeadLetters returns every dead letter, oldest first.
+func (service *Impl) GetDeadLetters() []entities.DeadLetter {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	deadLetters := make([]entities.DeadLetter, 0, len(service.deadLetters))
+	for _, deadLetter := range service.deadLetters {
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+
+	sort.Slice(deadLetters, func(i, j int) bool {
+		return deadLetters[i].ID < deadLetters[j].ID
+	})
+
+	return deadLetters
+}
+
+// Requeue forgets a dead letter and returns its message with the context it was
+// received with, ready to be treated again.
+func (service *Impl) Requeue(id uint) (amqp.Context, *amqp.RabbitMQMessage, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	deadLetter, found := service.deadLetters[id]
+	if !found {
+		return amqp.Context{}, nil, fmt.Errorf("%w: %d", errDeadLetterNotFound, id)
+	}
+
+	var message amqp.RabbitMQMessage
+	if err := proto.Unmarshal(deadLetter.Payload, &message); err != nil {
+		return amqp.Context{}, nil, err
+	}
+
+	ctx := amqp.Context{
+		Context:       context.Background(),
+		CorrelationID: deadLetter.CorrelationID,
+		RoutingKey:    deadLetter.RoutingKey,
+		ReplyTo:       deadLetter.ReplyTo,
+		Timestamp:     time.Now(),
+	}
+
+	service.forget(id)
+	return ctx, &message, nil
+}
+
+func (service *Impl) track(deadLetter *entities.DeadLetter) {
+	service.deadLetters[deadLetter.ID] = deadLetter
+	if deadLetter.CorrelationID != "" {
+		service.correlations[deadLetter.CorrelationID] = deadLetter.ID
+	}
+}
+
+func (service *Impl) forget(id uint) {
+	if err := service.deadLetterRepo.DeleteDeadLetter(id); err != nil {
+		log.Error().Err(err).Uint(constants.LogDeadLetterID, id).
+			Msgf("Cannot delete dead letter, it will be loaded again at next startup")
+	}
+
+	service.untrack(id)
+	service.updateGauge()
+}
+
+func (service *Impl) untrack(id uint) {
+	deadLetter := service.deadLetters[id]
+	delete(service.deadLetters, id)
+	if service.correlations[deadLetter.CorrelationID] == id {
+		delete(service.correlations, deadLetter.CorrelationID)
+	}
+}
+
+func (service *Impl) updateGauge() {
+	quarantined := 0
+	for _, deadLetter := range service.deadLetters {
+		if deadLetter.Quarantined {
+			quarantined++
+		}
+	}
+	insights.QuarantinedRequests.Set(float64(quarantined))
+}
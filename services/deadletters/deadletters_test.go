@@ -0,0 +1,154 @@
+package deadletters
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mockdeadletters "github.com/kaellybot/kaelly-portals/mocks/deadletters"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"google.golang.org/protobuf/proto"
+)
+
+const maxEntries = 3
+
+var errTest = errors.New("test")
+
+func TestRecord(t *testing.T) {
+	service := newTestService(t, mockdeadletters.New())
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	message := &amqp.RabbitMQMessage{Type: amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST}
+
+	service.Record(ctx, message, errTest, false)
+	if service.IsQuarantined(ctx.CorrelationID) {
+		t.Fatal("request quarantined after its first failure")
+	}
+
+	service.Record(ctx, message, errTest, false)
+	deadLetters := service.GetDeadLetters()
+	if len(deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters))
+	}
+	if deadLetters[0].Attempts != 2 || !deadLetters[0].Quarantined || deadLetters[0].Error != errTest.Error() {
+		t.Errorf("unexpected dead letter: %+v", deadLetters[0])
+	}
+
+	service.Record(amqp.Context{Context: context.Background()}, message, errTest, true)
+	service.Record(amqp.Context{Context: context.Background()}, message, errTest, true)
+	if count := len(service.GetDeadLetters()); count != 3 {
+		t.Errorf("messages without correlation ID must not be merged, got %d dead letters", count)
+	}
+}
+
+func TestNewLoadsDeadLetters(t *testing.T) {
+	service := newTestService(t, mockdeadletters.New(
+		entities.DeadLetter{CorrelationID: "quarantined", Quarantined: true},
+		entities.DeadLetter{CorrelationID: "failing"},
+	))
+
+	if !service.IsQuarantined("quarantined") || service.IsQuarantined("failing") {
+		t.Error("dead letters not loaded from repository")
+	}
+}
+
+func TestReloadReplacesDeadLetters(t *testing.T) {
+	repo := mockdeadletters.New()
+	service := newTestService(t, repo)
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "recorded"}
+	if err := repo.SaveDeadLetter(&entities.DeadLetter{CorrelationID: "quarantined", Quarantined: true}); err != nil {
+		t.Fatal(err)
+	}
+	service.Record(ctx, &amqp.RabbitMQMessage{}, errTest, true)
+
+	if err := service.Reload(); err != nil {
+		t.Fatalf("cannot reload: %v", err)
+	}
+	if !service.IsQuarantined("quarantined") || !service.IsQuarantined("recorded") {
+		t.Error("dead letters not loaded again from repository")
+	}
+	if count := len(service.GetDeadLetters()); count != 2 {
+		t.Errorf("expected 2 dead letters, got %d", count)
+	}
+}
+
+func TestNewPurgesOutdatedDeadLetters(t *testing.T) {
+	repo := mockdeadletters.New(
+		entities.DeadLetter{CorrelationID: "outdated", Quarantined: true, UpdatedAt: time.Now().Add(-2 * time.Hour)},
+		entities.DeadLetter{CorrelationID: "recent", Quarantined: true},
+	)
+	service := newTestService(t, repo)
+
+	if service.IsQuarantined("outdated") || !service.IsQuarantined("recent") {
+		t.Error("outdated dead letter not purged")
+	}
+	if deadLetters, _ := repo.GetDeadLetters(); len(deadLetters) != 1 {
+		t.Errorf("outdated dead letter not deleted: %+v", deadLetters)
+	}
+}
+
+func TestRecordEvictsOldestDeadLetters(t *testing.T) {
+	repo := mockdeadletters.New()
+	service := newTestService(t, repo)
+	message := &amqp.RabbitMQMessage{Type: amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST}
+	for _, correlationID := range []string{"first", "second", "third", "fourth"} {
+		ctx := amqp.Context{Context: context.Background(), CorrelationID: correlationID}
+		service.Record(ctx, message, errTest, true)
+	}
+
+	deadLetters := service.GetDeadLetters()
+	if len(deadLetters) != maxEntries || deadLetters[0].CorrelationID != "second" {
+		t.Errorf("expected the %d most recent dead letters, got %+v", maxEntries, deadLetters)
+	}
+	if stored, _ := repo.GetDeadLetters(); len(stored) != maxEntries {
+		t.Errorf("evicted dead letter not deleted: %+v", stored)
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	repo := mockdeadletters.New()
+	service := newTestService(t, repo)
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply",
+		RoutingKey: "requests.portals"}
+	message := &amqp.RabbitMQMessage{Type: amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST,
+		Language: amqp.Language_FR}
+	service.Record(ctx, message, errTest, true)
+
+	requeuedCtx, requeued, err := service.Requeue(service.GetDeadLetters()[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !proto.Equal(requeued, message) {
+		t.Errorf("expected %v, got %v", message, requeued)
+	}
+	if requeuedCtx.CorrelationID != ctx.CorrelationID || requeuedCtx.ReplyTo != ctx.ReplyTo ||
+		requeuedCtx.RoutingKey != ctx.RoutingKey {
+		t.Errorf("unexpected context: %+v", requeuedCtx)
+	}
+	if service.IsQuarantined(ctx.CorrelationID) {
+		t.Error("requeued request still quarantined")
+	}
+	if deadLetters, _ := repo.GetDeadLetters(); len(deadLetters) != 0 {
+		t.Errorf("requeued dead letter not deleted: %+v", deadLetters)
+	}
+
+	if _, _, err = service.Requeue(42); !errors.Is(err, errDeadLetterNotFound) {
+		t.Errorf("expected %v, got %v", errDeadLetterNotFound, err)
+	}
+}
+
+func newTestService(t *testing.T, repo *mockdeadletters.Repository) *Impl {
+	t.Helper()
+	service, err := New(configs.Config{
+		DeadLetterMaxAttempts: 2,
+		DeadLetterRetention:   time.Hour,
+		DeadLetterMaxEntries:  maxEntries,
+	}, repo)
+	if err != nil {
+		t.Fatalf("cannot build service: %v", err)
+	}
+	return service
+}
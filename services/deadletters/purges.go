@@ -0,0 +1,82 @@
+package deadletters
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/rs/zerolog/log"
+)
+
+// Start purges outdated dead letters in background until Stop is called.
+func (service *Impl) Start() {
+	service.stop = make(chan struct{})
+	service.done = make(chan struct{})
+
+	go func() {
+		defer close(service.done)
+		ticker := time.NewTicker(purgeInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-service.stop:
+				return
+			case <-ticker.C:
+				service.purge()
+			}
+		}
+	}()
+}
+
+// Stop waits for the running purge, if any, to complete.
+func (service *Impl) Stop() {
+	if service.stop == nil {
+		return
+	}
+
+	close(service.stop)
+	<-service.done
+	service.stop = nil
+}
+
+// purge deletes the dead letters that did not fail again for the retention duration.
+func (service *Impl) purge() {
+	before := time.Now().Add(-service.retention)
+	if err := service.deadLetterRepo.DeleteDeadLettersBefore(before); err != nil {
+		log.Error().Err(err).Msgf("Cannot purge dead letters, retrying at next purge")
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for id, deadLetter := range service.deadLetters {
+		if deadLetter.UpdatedAt.Before(before) {
+			service.untrack(id)
+		}
+	}
+	service.updateGauge()
+}
+
+// evict forgets the least recently failing dead letters beyond the maximum number of entries.
+func (service *Impl) evict() {
+	overflow := len(service.deadLetters) - service.maxEntries
+	if overflow <= 0 {
+		return
+	}
+
+	deadLetters := make([]*entities.DeadLetter, 0, len(service.deadLetters))
+	for _, deadLetter := range service.deadLetters {
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	sort.Slice(deadLetters, func(i, j int) bool {
+		if deadLetters[i].UpdatedAt.Equal(deadLetters[j].UpdatedAt) {
+			return deadLetters[i].ID < deadLetters[j].ID
+		}
+		return deadLetters[i].UpdatedAt.Before(deadLetters[j].UpdatedAt)
+	})
+
+	for _, deadLetter := range deadLetters[:overflow] {
+		service.forget(deadLetter.ID)
+	}
+}
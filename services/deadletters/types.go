@@ -0,0 +1,46 @@
+package deadletters
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/deadletters"
+)
+
+const (
+	statusFailing     = "failing"
+	statusQuarantined = "quarantined"
+
+	purgeInterval = time.Hour
+)
+
+var errDeadLetterNotFound = errors.New("dead letter not found")
+
+type Service interface {
+	Record(ctx amqp.Context, message *amqp.RabbitMQMessage, cause error, poison bool)
+	Resolve(correlationID string)
+	IsQuarantined(correlationID string) bool
+	GetDeadLetters() []entities.DeadLetter
+	Requeue(id uint) (amqp.Context, *amqp.RabbitMQMessage, error)
+	Reload() error
+	Start()
+	Stop()
+}
+
+// Impl keeps track of failing requests, keyed by correlation ID when provided.
+// Once quarantined, a request is no longer treated until requeued.
+// Dead letters are purged once older than the retention or beyond the maximum number of entries.
+type Impl struct {
+	mutex          sync.Mutex
+	maxAttempts    int
+	retention      time.Duration
+	maxEntries     int
+	deadLetters    map[uint]*entities.DeadLetter
+	correlations   map[string]uint
+	deadLetterRepo deadletters.Repository
+	stop           chan struct{}
+	done           chan struct{}
+}
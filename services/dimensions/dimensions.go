@@ -6,11 +6,24 @@ import (
 )
 
 func New(dimensionRepo dimensions.Repository) (*Impl, error) {
-	dimEntities, err := dimensionRepo.GetDimensions()
-	if err != nil {
+	service := Impl{
+		dimensionRepo: dimensionRepo,
+	}
+
+	if err := service.Reload(); err != nil {
 		return nil, err
 	}
 
+	return &service, nil
+}
+
+// Reload loads dimensions again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	dimEntities, err := service.dimensionRepo.GetDimensions()
+	if err != nil {
+		return err
+	}
+
 	dimensions := make(map[string]entities.Dimension)
 	dofusPortalsDimensions := make(map[string]entities.Dimension)
 	for _, dimension := range dimEntities {
@@ -18,19 +31,23 @@ func New(dimensionRepo dimensions.Repository) (*Impl, error) {
 		dofusPortalsDimensions[dimension.DofusPortalsID] = dimension
 	}
 
-	return &Impl{
-		dimensions:             dimensions,
-		dofusPortalsDimensions: dofusPortalsDimensions,
-		dimensionRepo:          dimensionRepo,
-	}, nil
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.dimensions = dimensions
+	service.dofusPortalsDimensions = dofusPortalsDimensions
+	return nil
 }
 
 func (service *Impl) GetDimension(id string) (entities.Dimension, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	dimension, found := service.dimensions[id]
 	return dimension, found
 }
 
 func (service *Impl) FindDimensionByDofusPortalsID(dofusPortalsID string) (entities.Dimension, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	dimension, found := service.dofusPortalsDimensions[dofusPortalsID]
 	return dimension, found
 }
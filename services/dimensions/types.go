@@ -1,6 +1,8 @@
 package dimensions
 
 import (
+	"sync"
+
 	"github.com/kaellybot/kaelly-portals/models/entities"
 	"github.com/kaellybot/kaelly-portals/repositories/dimensions"
 )
@@ -8,9 +10,11 @@ import (
 type Service interface {
 	GetDimension(id string) (entities.Dimension, bool)
 	FindDimensionByDofusPortalsID(dofusPortalsID string) (entities.Dimension, bool)
+	Reload() error
 }
 
 type Impl struct {
+	mutex                  sync.RWMutex
 	dimensions             map[string]entities.Dimension
 	dofusPortalsDimensions map[string]entities.Dimension
 	dimensionRepo          dimensions.Repository
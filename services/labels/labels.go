@@ -0,0 +1,110 @@
+package labels
+
+import (
+	"fmt"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/repositories/labels"
+)
+
+func New(labelRepo labels.Repository) (*Impl, error) {
+	service := Impl{
+		labelRepo: labelRepo,
+	}
+
+	if err := service.Reload(); err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+// Reload loads labels again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	labelEntities, err := service.labelRepo.GetLabels()
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[string]string)
+	for _, label := range labelEntities {
+		labels[getKey(label.ReferenceType, label.ReferenceID, label.Language)] = label.Label
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.labels = labels
+	return nil
+}
+
+// Localize returns ready-to-display labels of a position, indexed by field.
+// Fields without value, such as a missing transport, are omitted.
+func (service *Impl) Localize(position *amqp.PortalPositionAnswer_PortalPosition,
+	language amqp.Language) map[string]string {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	result := make(map[string]string)
+	service.put(result, fieldDimension, referenceDimension, position.GetDimensionId(), language)
+
+	transport := position.GetPosition().GetTransport()
+	service.put(result, fieldArea, referenceArea, transport.GetAreaId(), language)
+	service.put(result, fieldSubArea, referenceSubArea, transport.GetSubAreaId(), language)
+	service.put(result, fieldTransportType, referenceTransportType, transport.GetTypeId(), language)
+
+	conditional := position.GetPosition().GetConditionalTransport()
+	service.put(result, fieldConditionalArea, referenceArea, conditional.GetAreaId(), language)
+	service.put(result, fieldConditionalSubArea, referenceSubArea, conditional.GetSubAreaId(), language)
+	service.put(result, fieldConditionalTransportType, referenceTransportType, conditional.GetTypeId(), language)
+
+	if hint := formatRemainingUses(position.GetRemainingUses(), language); hint != "" {
+		result[fieldRemainingUses] = hint
+	}
+
+	return result
+}
+
+func (service *Impl) put(result map[string]string, field, referenceType, referenceID string,
+	language amqp.Language) {
+	if referenceID != "" {
+		result[field] = service.getLabel(referenceType, referenceID, language)
+	}
+}
+
+func (service *Impl) getLabel(referenceType, referenceID string, language amqp.Language) string {
+	if label, found := service.labels[getKey(referenceType, referenceID, language)]; found {
+		return label
+	}
+
+	if label, found := service.labels[getKey(referenceType, referenceID, defaultLanguage)]; found {
+		return label
+	}
+
+	return referenceID
+}
+
+// formatRemainingUses returns an empty hint for unknown remaining uses, reported as 0.
+func formatRemainingUses(remainingUses int64, language amqp.Language) string {
+	if remainingUses <= 0 {
+		return ""
+	}
+
+	hints := remainingUsesHints
+	if remainingUses == 1 {
+		hints = remainingUseHints
+	}
+
+	hint, found := hints[language]
+	if !found {
+		hint = hints[defaultLanguage]
+	}
+
+	if remainingUses == 1 {
+		return hint
+	}
+
+	return fmt.Sprintf(hint, remainingUses)
+}
+
+func getKey(referenceType, referenceID string, language amqp.Language) string {
+	return fmt.Sprintf("%s/%s/%d", referenceType, referenceID, language)
+}
@@ -0,0 +1,96 @@
+package labels
+
+import (
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+)
+
+func TestLocalize(t *testing.T) {
+	service, err := New(references.Labels{
+		{ReferenceType: referenceDimension, ReferenceID: "enu", Language: amqp.Language_FR, Label: "Enutrosor"},
+		{ReferenceType: referenceDimension, ReferenceID: "enu", Language: amqp.Language_EN, Label: "Enurado"},
+		{ReferenceType: referenceArea, ReferenceID: "astrub", Language: amqp.Language_EN, Label: "Astrub"},
+		{ReferenceType: referenceSubArea, ReferenceID: "city", Language: amqp.Language_FR, Label: "Cité d'Astrub"},
+		{ReferenceType: referenceTransportType, ReferenceID: "zaap", Language: amqp.Language_FR, Label: "Zaap"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	position := &amqp.PortalPositionAnswer_PortalPosition{
+		DimensionId:   "enu",
+		RemainingUses: 42,
+		Position: &amqp.PortalPositionAnswer_PortalPosition_Position{
+			Transport: &amqp.PortalPositionAnswer_PortalPosition_Position_Transport{
+				AreaId: "astrub", SubAreaId: "city", TypeId: "zaap",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		language amqp.Language
+		expected map[string]string
+	}{
+		{
+			name:     "french",
+			language: amqp.Language_FR,
+			expected: map[string]string{
+				fieldDimension: "Enutrosor", fieldArea: "Astrub", fieldSubArea: "Cité d'Astrub",
+				fieldTransportType: "Zaap", fieldRemainingUses: "42 utilisations restantes",
+			},
+		},
+		{
+			name:     "missing translations",
+			language: amqp.Language_DE,
+			expected: map[string]string{
+				fieldDimension: "Enurado", fieldArea: "Astrub", fieldSubArea: "city",
+				fieldTransportType: "zaap", fieldRemainingUses: "42 verbleibende Nutzungen",
+			},
+		},
+		{
+			name:     "any language",
+			language: amqp.Language_ANY,
+			expected: map[string]string{
+				fieldDimension: "Enurado", fieldArea: "Astrub", fieldSubArea: "city",
+				fieldTransportType: "zaap", fieldRemainingUses: "42 uses left",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := service.Localize(position, test.language)
+			if len(result) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, result)
+			}
+			for field, expected := range test.expected {
+				if result[field] != expected {
+					t.Errorf("%s: expected %q, got %q", field, expected, result[field])
+				}
+			}
+		})
+	}
+}
+
+func TestFormatRemainingUses(t *testing.T) {
+	tests := []struct {
+		remainingUses int64
+		language      amqp.Language
+		expected      string
+	}{
+		{remainingUses: 0, language: amqp.Language_EN, expected: ""},
+		{remainingUses: 1, language: amqp.Language_EN, expected: "1 use left"},
+		{remainingUses: 1, language: amqp.Language_ES, expected: "1 uso restante"},
+		{remainingUses: 12, language: amqp.Language_ES, expected: "12 usos restantes"},
+		{remainingUses: 12, language: amqp.Language_ANY, expected: "12 uses left"},
+	}
+
+	for _, test := range tests {
+		if result := formatRemainingUses(test.remainingUses, test.language); result != test.expected {
+			t.Errorf("%d in %v: expected %q, got %q", test.remainingUses, test.language, test.expected, result)
+		}
+	}
+}
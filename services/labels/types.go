@@ -0,0 +1,55 @@
+package labels
+
+import (
+	"sync"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/repositories/labels"
+)
+
+const (
+	referenceDimension     = "dimension"
+	referenceArea          = "area"
+	referenceSubArea       = "sub_area"
+	referenceTransportType = "transport_type"
+
+	fieldDimension                = "dimension"
+	fieldArea                     = "area"
+	fieldSubArea                  = "subArea"
+	fieldTransportType            = "transportType"
+	fieldConditionalArea          = "conditionalArea"
+	fieldConditionalSubArea       = "conditionalSubArea"
+	fieldConditionalTransportType = "conditionalTransportType"
+	fieldRemainingUses            = "remainingUses"
+
+	defaultLanguage = amqp.Language_EN
+)
+
+//nolint:gochecknoglobals // Read-only lookups, indexed by language.
+var (
+	remainingUseHints = map[amqp.Language]string{
+		amqp.Language_FR: "1 utilisation restante",
+		amqp.Language_EN: "1 use left",
+		amqp.Language_ES: "1 uso restante",
+		amqp.Language_DE: "1 verbleibende Nutzung",
+	}
+	remainingUsesHints = map[amqp.Language]string{
+		amqp.Language_FR: "%d utilisations restantes",
+		amqp.Language_EN: "%d uses left",
+		amqp.Language_ES: "%d usos restantes",
+		amqp.Language_DE: "%d verbleibende Nutzungen",
+	}
+)
+
+type Service interface {
+	Localize(position *amqp.PortalPositionAnswer_PortalPosition, language amqp.Language) map[string]string
+	Reload() error
+}
+
+// Impl resolves reference labels in the requested language, falling back
+// to English and then to the reference ID itself.
+type Impl struct {
+	mutex     sync.RWMutex
+	labels    map[string]string
+	labelRepo labels.Repository
+}
@@ -0,0 +1,151 @@
+package pollers
+
+import (
+	"context"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/proto"
+)
+
+func New(interval time.Duration, portalService portals.Service,
+	subscriptionService subscriptions.Service) *Impl {
+	return &Impl{
+		interval:            interval,
+		portalService:       portalService,
+		subscriptionService: subscriptionService,
+		positions:           make(map[string]map[string]*amqp.PortalPositionAnswer_PortalPosition),
+	}
+}
+
+// Start polls in background until Stop is called; it does nothing if interval is not positive.
+func (service *Impl) Start() {
+	service.lifecycle.Lock()
+	defer service.lifecycle.Unlock()
+	service.started = true
+	service.run()
+}
+
+// Stop waits for the running poll, if any, to complete.
+func (service *Impl) Stop() {
+	service.lifecycle.Lock()
+	defer service.lifecycle.Unlock()
+	service.started = false
+	service.halt()
+}
+
+func (service *Impl) Reload(interval time.Duration) {
+	service.lifecycle.Lock()
+	defer service.lifecycle.Unlock()
+	if interval == service.interval {
+		return
+	}
+
+	service.halt()
+	service.interval = interval
+	if service.started {
+		service.run()
+	}
+}
+
+func (service *Impl) run() {
+	if service.interval <= 0 {
+		log.Info().Msgf("Portal polling is disabled")
+		return
+	}
+
+	service.stop = make(chan struct{})
+	service.done = make(chan struct{})
+	log.Info().Msgf("Polling portals of subscribed servers every %v...", service.interval)
+
+	go func() {
+		defer close(service.done)
+		ticker := time.NewTicker(service.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-service.stop:
+				return
+			case <-ticker.C:
+				service.poll(context.Background())
+			}
+		}
+	}()
+}
+
+func (service *Impl) halt() {
+	if service.stop == nil {
+		return
+	}
+
+	close(service.stop)
+	<-service.done
+	service.stop = nil
+}
+
+func (service *Impl) poll(ctx context.Context) {
+	serverIDs, err := service.subscriptionService.GetSubscribedServerIDs()
+	if err != nil {
+		log.Error().Err(err).Msgf("Cannot retrieve subscribed servers, poll ignored")
+		insights.Polls.WithLabelValues(statusError).Inc()
+		return
+	}
+
+	for _, serverID := range serverIDs {
+		// Errors are already logged and counted, the other servers are still polled.
+		_ = service.PollServer(ctx, serverID)
+	}
+}
+
+// PollServer retrieves the portals of a server right away and notifies subscriptions
+// of their changes, as a periodic poll would.
+func (service *Impl) PollServer(ctx context.Context, serverID string) error {
+	ctx, span := insights.StartSpan(ctx, "portals.poll", insights.AttributeServerID.String(serverID))
+	defer span.End()
+
+	positions, err := service.portalService.GetPortals(ctx, serverID, "")
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogServerID, serverID).Msgf("Cannot poll portals, server ignored")
+		insights.Polls.WithLabelValues(statusError).Inc()
+		insights.EndSpan(span, err)
+		return err
+	}
+	insights.Polls.WithLabelValues(statusSuccess).Inc()
+
+	service.mutex.Lock()
+	previousPositions, known := service.positions[serverID]
+	currentPositions := make(map[string]*amqp.PortalPositionAnswer_PortalPosition)
+	for _, position := range positions {
+		currentPositions[position.GetDimensionId()] = position
+	}
+	service.positions[serverID] = currentPositions
+	service.mutex.Unlock()
+
+	// The first poll of a server only sets the baseline to compare with.
+	if !known {
+		return nil
+	}
+
+	for dimensionID, current := range currentPositions {
+		previous := previousPositions[dimensionID]
+		if hasChanged(previous, current) {
+			service.subscriptionService.Notify(ctx, previous, current)
+		}
+	}
+
+	return nil
+}
+
+func hasChanged(previous, current *amqp.PortalPositionAnswer_PortalPosition) bool {
+	if current.GetPosition() == nil {
+		return false
+	}
+
+	return previous.GetPosition() == nil || !proto.Equal(previous.GetPosition(), current.GetPosition())
+}
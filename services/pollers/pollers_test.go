@@ -0,0 +1,126 @@
+package pollers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+)
+
+type fakePortals struct {
+	portals.Service
+	mutex     sync.Mutex
+	positions []*amqp.PortalPositionAnswer_PortalPosition
+}
+
+type fakeSubscriptions struct {
+	subscriptions.Service
+	mutex    sync.Mutex
+	notified []*amqp.PortalPositionAnswer_PortalPosition
+}
+
+func (fake *fakePortals) GetPortals(_ context.Context, _, _ string,
+) ([]*amqp.PortalPositionAnswer_PortalPosition, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.positions, nil
+}
+
+func (fake *fakePortals) set(positions ...*amqp.PortalPositionAnswer_PortalPosition) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.positions = positions
+}
+
+func (fake *fakeSubscriptions) GetSubscribedServerIDs() ([]string, error) {
+	return []string{"imagiro"}, nil
+}
+
+func (fake *fakeSubscriptions) Notify(_ context.Context, _, current *amqp.PortalPositionAnswer_PortalPosition) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.notified = append(fake.notified, current)
+}
+
+func (fake *fakeSubscriptions) count() int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return len(fake.notified)
+}
+
+func newPosition(dimensionID string, x int64) *amqp.PortalPositionAnswer_PortalPosition {
+	return &amqp.PortalPositionAnswer_PortalPosition{
+		ServerId:    "imagiro",
+		DimensionId: dimensionID,
+		Position:    &amqp.PortalPositionAnswer_PortalPosition_Position{X: x},
+	}
+}
+
+func TestPoll(t *testing.T) {
+	portalService := &fakePortals{}
+	subscriptionService := &fakeSubscriptions{}
+	service := New(time.Minute, portalService, subscriptionService)
+
+	portalService.set(newPosition("enutrosor", 1), &amqp.PortalPositionAnswer_PortalPosition{
+		ServerId: "imagiro", DimensionId: "srambad"})
+	service.poll(context.Background())
+	if count := subscriptionService.count(); count != 0 {
+		t.Fatalf("first poll must only set the baseline, got %d notifications", count)
+	}
+
+	service.poll(context.Background())
+	if count := subscriptionService.count(); count != 0 {
+		t.Fatalf("unchanged positions must not be notified, got %d notifications", count)
+	}
+
+	moved, appeared := newPosition("enutrosor", 2), newPosition("srambad", 3)
+	portalService.set(moved, appeared, newPosition("xelorium", 0))
+	service.poll(context.Background())
+	if count := subscriptionService.count(); count != 3 {
+		t.Errorf("expected moved, appeared and new dimension positions to be notified, got %d", count)
+	}
+}
+
+func TestStartDisabled(t *testing.T) {
+	service := New(0, &fakePortals{}, &fakeSubscriptions{})
+	service.Start()
+	service.Stop()
+}
+
+func TestStartStop(t *testing.T) {
+	service := New(time.Millisecond, &fakePortals{}, &fakeSubscriptions{})
+	service.Start()
+	time.Sleep(10 * time.Millisecond)
+	service.Stop()
+}
+
+func TestReload(t *testing.T) {
+	portalService := &fakePortals{}
+	subscriptionService := &fakeSubscriptions{}
+	service := New(0, portalService, subscriptionService)
+	service.Start()
+	defer service.Stop()
+
+	service.Reload(time.Millisecond)
+	deadline := time.After(time.Second)
+	for x := int64(0); subscriptionService.count() == 0; x++ {
+		portalService.set(newPosition("enu", x))
+		select {
+		case <-deadline:
+			t.Fatal("polling not enabled by reload")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	service.Reload(0)
+	count := subscriptionService.count()
+	portalService.set(newPosition("enu", 2))
+	time.Sleep(10 * time.Millisecond)
+	if subscriptionService.count() != count {
+		t.Error("polling not disabled by reload")
+	}
+}
@@ -0,0 +1,38 @@
+package pollers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/services/portals"
+	"github.com/kaellybot/kaelly-portals/services/subscriptions"
+)
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+)
+
+type Service interface {
+	Start()
+	Stop()
+	// Reload applies a new poll interval, restarting the polling if started; 0 disables it.
+	Reload(interval time.Duration)
+	PollServer(ctx context.Context, serverID string) error
+}
+
+// Impl periodically retrieves the portals of subscribed servers and notifies
+// subscriptions of each position that appeared or moved since the previous poll.
+type Impl struct {
+	interval            time.Duration
+	portalService       portals.Service
+	subscriptionService subscriptions.Service
+	mutex               sync.Mutex
+	positions           map[string]map[string]*amqp.PortalPositionAnswer_PortalPosition
+	lifecycle           sync.Mutex
+	started             bool
+	stop                chan struct{}
+	done                chan struct{}
+}
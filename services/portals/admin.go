@@ -0,0 +1,36 @@
+package portals
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+// FlushCache forgets the last known positions, used to answer requests that cannot reach dofus-portals.
+func (service *Impl) FlushCache() {
+	service.cache.flush()
+	log.Info().Msgf("Portal position cache flushed")
+}
+
+// SetEnabled toggles the dofus-portals source until DOFUS_PORTALS_ENABLED changes, other reloads keep it.
+func (service *Impl) SetEnabled(enabled bool) {
+	if service.enabled.Swap(enabled) != enabled {
+		log.Info().Msgf("Dofus Portals source enabled: %v", enabled)
+	}
+}
+
+// GetStats returns a view of the internal state of the service, for operators.
+func (service *Impl) GetStats() Stats {
+	healthyEndpoints := 0
+	for _, endpoint := range service.endpoints {
+		if endpoint.isHealthy() {
+			healthyEndpoints++
+		}
+	}
+
+	return Stats{
+		SourceEnabled:    service.enabled.Load(),
+		InFlightRequests: service.requests.size(),
+		CachedPositions:  service.cache.size(),
+		Endpoints:        len(service.endpoints),
+		HealthyEndpoints: healthyEndpoints,
+	}
+}
@@ -0,0 +1,123 @@
+package portals
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/kaellybot/kaelly-portals/utils/recorders"
+	"github.com/rs/zerolog/log"
+)
+
+func newCredentials(doer recorders.HTTPRequestDoer, primary, secondary tokenSource) *credentials {
+	return &credentials{
+		doer:   doer,
+		tokens: []*tokenSource{&primary, &secondary},
+		active: primaryToken,
+	}
+}
+
+// Do authenticates the request with the active token. If dofus-portals rejects it,
+// the other token is activated and the request is sent once again.
+func (credentials *credentials) Do(req *http.Request) (*http.Response, error) {
+	token := credentials.getToken()
+	resp, err := credentials.send(req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isTokenRejected(resp) || !credentials.rotate(token) {
+		return resp, nil
+	}
+
+	resp.Body.Close()
+	return credentials.send(req, credentials.getToken())
+}
+
+func (credentials *credentials) send(req *http.Request, token string) (*http.Response, error) {
+	authenticatedReq := req.Clone(req.Context())
+	authenticatedReq.Header.Set(httpAPIToken, token)
+	return credentials.doer.Do(authenticatedReq)
+}
+
+func (credentials *credentials) getToken() string {
+	credentials.mutex.Lock()
+	defer credentials.mutex.Unlock()
+	return credentials.tokens[credentials.active].get()
+}
+
+// rotate activates the other token if the rejected one is still the active one
+// and if the other one is filled. It returns true if the request can be retried.
+func (credentials *credentials) rotate(rejectedToken string) bool {
+	credentials.mutex.Lock()
+	defer credentials.mutex.Unlock()
+
+	if credentials.tokens[credentials.active].get() != rejectedToken {
+		// Already rotated by a concurrent request.
+		return true
+	}
+
+	next := (credentials.active + 1) % len(credentials.tokens)
+	if credentials.tokens[next].get() == "" {
+		log.Error().Str(constants.LogToken, tokenNames[credentials.active]).
+			Msgf("Dofus Portals token rejected and no other token is available, please check configuration")
+		return false
+	}
+
+	log.Error().Str(constants.LogToken, tokenNames[credentials.active]).
+		Msgf("Dofus Portals token rejected, switching to %s token", tokenNames[next])
+	credentials.active = next
+	insights.TokenSwitches.WithLabelValues(tokenNames[next]).Inc()
+	return true
+}
+
+// get returns the token, reading it from file if one is configured;
+// the file is read again periodically to take rotations into account.
+func (source *tokenSource) get() string {
+	if source.file == "" || time.Since(source.readAt) < tokenRefreshInterval {
+		return source.value
+	}
+
+	source.readAt = time.Now()
+	data, err := os.ReadFile(source.file)
+	if err != nil {
+		log.Error().Err(err).Str(constants.LogFileName, source.file).
+			Msgf("Cannot read token file, keeping the previous token")
+		return source.value
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token != source.value {
+		log.Info().Str(constants.LogFileName, source.file).Msgf("Token loaded from file")
+		source.value = token
+	}
+
+	return source.value
+}
+
+func isTokenRejected(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return true
+	case http.StatusBadRequest:
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return false
+		}
+
+		var intendedError dofusportals.IntendedError
+		return json.Unmarshal(body, &intendedError) == nil &&
+			intendedError.Error == dofusportals.TokenNotFound
+	default:
+		return false
+	}
+}
@@ -0,0 +1,117 @@
+package portals
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+)
+
+func TestCredentialsRotation(t *testing.T) {
+	tests := []struct {
+		name               string
+		primary, secondary string
+		expectedStatus     int
+		expectedActive     int
+	}{
+		{name: "valid primary", primary: token, secondary: "other",
+			expectedStatus: http.StatusOK, expectedActive: primaryToken},
+		{name: "rejected primary", primary: "revoked", secondary: token,
+			expectedStatus: http.StatusOK, expectedActive: primaryToken + 1},
+		{name: "rejected primary without secondary", primary: "revoked",
+			expectedStatus: http.StatusUnauthorized, expectedActive: primaryToken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := mockportals.New(token)
+			t.Cleanup(fake.Close)
+
+			credentials := newCredentials(&http.Client{},
+				tokenSource{value: test.primary}, tokenSource{value: test.secondary})
+			status := doRequest(t, credentials, fake.URL)
+
+			if status != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, status)
+			}
+			if credentials.active != test.expectedActive {
+				t.Errorf("expected active token %d, got %d", test.expectedActive, credentials.active)
+			}
+		})
+	}
+}
+
+func TestTokenFileReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("revoked\n"), 0o600); err != nil {
+		t.Fatalf("cannot write token file: %v", err)
+	}
+
+	source := tokenSource{value: "from env", file: file}
+	if value := source.get(); value != "revoked" {
+		t.Errorf("expected token from file, got %q", value)
+	}
+
+	if err := os.WriteFile(file, []byte(token), 0o600); err != nil {
+		t.Fatalf("cannot write token file: %v", err)
+	}
+	if value := source.get(); value != "revoked" {
+		t.Errorf("expected token to be cached until next refresh, got %q", value)
+	}
+
+	source.readAt = time.Now().Add(-tokenRefreshInterval)
+	if value := source.get(); value != token {
+		t.Errorf("expected token to be reloaded, got %q", value)
+	}
+}
+
+func doRequest(t *testing.T, credentials *credentials, url string) int {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url+"/external/v1/servers", nil)
+	if err != nil {
+		t.Fatalf("cannot build request: %v", err)
+	}
+
+	resp, err := credentials.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestIsTokenRejected(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   bool
+	}{
+		{name: "ok", statusCode: http.StatusOK, body: "[]", expected: false},
+		{name: "unauthorized", statusCode: http.StatusUnauthorized, expected: true},
+		{name: "forbidden", statusCode: http.StatusForbidden, expected: true},
+		{name: "token not found", statusCode: http.StatusBadRequest, body: `{"error":"token.not_found"}`, expected: true},
+		{name: "server not found", statusCode: http.StatusBadRequest, body: `{"error":"server.not_found"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: test.statusCode, Body: io.NopCloser(strings.NewReader(test.body))}
+			if result := isTokenRejected(resp); result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil || string(body) != test.body {
+				t.Errorf("body must stay readable, got %q (%v)", body, err)
+			}
+		})
+	}
+}
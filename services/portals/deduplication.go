@@ -0,0 +1,102 @@
+package portals
+
+import (
+	"context"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+)
+
+func newDeduplicator(ttl time.Duration) *deduplicator {
+	deduplicator := deduplicator{
+		entries: make(map[string]*deduplicationEntry),
+	}
+	deduplicator.setTTL(ttl)
+
+	return &deduplicator
+}
+
+// setTTL applies to replies released from now on; 0 disables deduplication.
+func (deduplicator *deduplicator) setTTL(ttl time.Duration) {
+	deduplicator.ttl.Store(int64(ttl))
+}
+
+func (deduplicator *deduplicator) getTTL() time.Duration {
+	return time.Duration(deduplicator.ttl.Load())
+}
+
+// acquire returns the cached reply of an already answered correlation ID.
+// If none is found, the caller owns the correlation ID through the returned entry until
+// release is called; requests with the same correlation ID received meanwhile wait for it.
+// No entry is returned when ctx is done while waiting, the correlation ID being still owned
+// by the request treated meanwhile.
+func (deduplicator *deduplicator) acquire(ctx context.Context, correlationID string,
+) (*amqp.RabbitMQMessage, *deduplicationEntry) {
+	if deduplicator.getTTL() <= 0 || correlationID == "" {
+		return nil, nil
+	}
+
+	for {
+		deduplicator.mutex.Lock()
+		deduplicator.purge()
+		entry, found := deduplicator.entries[correlationID]
+		if !found {
+			owned := &deduplicationEntry{correlationID: correlationID, done: make(chan struct{})}
+			deduplicator.entries[correlationID] = owned
+			deduplicator.mutex.Unlock()
+			return nil, owned
+		}
+		deduplicator.mutex.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, nil
+		}
+
+		if entry.reply != nil {
+			return entry.reply, nil
+		}
+	}
+}
+
+// release caches the reply of a correlation ID owned through entry; a nil entry is ignored.
+// A nil reply forgets the correlation ID, so that a redelivery is treated again;
+// so does a disabled deduplication, the correlation ID having been acquired before.
+func (deduplicator *deduplicator) release(entry *deduplicationEntry, reply *amqp.RabbitMQMessage) {
+	if entry == nil {
+		return
+	}
+
+	deduplicator.mutex.Lock()
+	defer deduplicator.mutex.Unlock()
+	if deduplicator.entries[entry.correlationID] != entry || entry.isReleased() {
+		return
+	}
+
+	if ttl := deduplicator.getTTL(); reply != nil && ttl > 0 {
+		entry.reply = reply
+		entry.expiresAt = time.Now().Add(ttl)
+	} else {
+		delete(deduplicator.entries, entry.correlationID)
+	}
+	close(entry.done)
+}
+
+func (deduplicator *deduplicator) purge() {
+	now := time.Now()
+	for correlationID, entry := range deduplicator.entries {
+		if entry.reply != nil && now.After(entry.expiresAt) {
+			delete(deduplicator.entries, correlationID)
+		}
+	}
+}
+
+func (entry *deduplicationEntry) isReleased() bool {
+	select {
+	case <-entry.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+package portals
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+)
+
+func TestConsumeRedelivery(t *testing.T) {
+	service, fake, broker := newTestService(t)
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+
+	replies := broker.Replies()
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 replies, got %d", len(replies))
+	}
+	if replies[1].Message != replies[0].Message {
+		t.Errorf("redelivery not answered with the cached reply")
+	}
+	if requests := fake.Requests(); requests != 1 {
+		t.Errorf("expected 1 upstream request, got %d", requests)
+	}
+}
+
+func TestConsumeRedeliveryAfterFailure(t *testing.T) {
+	service, fake, broker := newTestService(t)
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	fake.Script(mockportals.Behaviour{Malformed: true})
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	fake.Script(mockportals.Behaviour{})
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+
+	replies := broker.Replies()
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 replies, got %d", len(replies))
+	}
+	if replies[0].Message.Status != amqp.RabbitMQMessage_FAILED ||
+		replies[1].Message.Status != amqp.RabbitMQMessage_SUCCESS {
+		t.Errorf("failed request not treated again on redelivery: %v, %v",
+			replies[0].Message.Status, replies[1].Message.Status)
+	}
+}
+
+func TestDeduplicatorConcurrentRequests(t *testing.T) {
+	deduplicator := newDeduplicator(time.Minute)
+	cached, owned := deduplicator.acquire(context.Background(), "correlation")
+	if cached != nil || owned == nil {
+		t.Fatal("expected the correlation ID to be owned")
+	}
+
+	reply := &amqp.RabbitMQMessage{Status: amqp.RabbitMQMessage_SUCCESS}
+	var waiting sync.WaitGroup
+	waiting.Add(1)
+	go func() {
+		defer waiting.Done()
+		cached, owned := deduplicator.acquire(context.Background(), "correlation")
+		if cached != reply || owned != nil {
+			t.Errorf("expected the reply of the first request, got %v", cached)
+		}
+	}()
+
+	deduplicator.release(owned, reply)
+	waiting.Wait()
+}
+
+func TestDeduplicatorCanceledWait(t *testing.T) {
+	deduplicator := newDeduplicator(time.Minute)
+	_, owned := deduplicator.acquire(context.Background(), "correlation")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cached, waiting := deduplicator.acquire(ctx, "correlation")
+	if cached != nil || waiting != nil {
+		t.Fatalf("expected neither reply nor ownership once canceled, got %v", cached)
+	}
+	deduplicator.release(waiting, nil)
+
+	reply := &amqp.RabbitMQMessage{Status: amqp.RabbitMQMessage_SUCCESS}
+	deduplicator.release(owned, reply)
+	if cached, _ := deduplicator.acquire(context.Background(), "correlation"); cached != reply {
+		t.Errorf("reply of the owner not cached, got %v", cached)
+	}
+}
+
+func TestDeduplicatorExpiration(t *testing.T) {
+	deduplicator := newDeduplicator(time.Millisecond)
+	_, owned := deduplicator.acquire(context.Background(), "correlation")
+	deduplicator.release(owned, &amqp.RabbitMQMessage{})
+
+	time.Sleep(2 * time.Millisecond)
+	if cached, _ := deduplicator.acquire(context.Background(), "correlation"); cached != nil {
+		t.Error("expired reply still answered")
+	}
+}
+
+func TestDeduplicatorDisabled(t *testing.T) {
+	deduplicator := newDeduplicator(0)
+	_, owned := deduplicator.acquire(context.Background(), "correlation")
+	deduplicator.release(owned, &amqp.RabbitMQMessage{})
+
+	if cached, _ := deduplicator.acquire(context.Background(), "correlation"); cached != nil {
+		t.Error("reply cached while deduplication is disabled")
+	}
+}
+
+func TestDeduplicatorDisabledWhileTreated(t *testing.T) {
+	deduplicator := newDeduplicator(time.Minute)
+	_, owned := deduplicator.acquire(context.Background(), "correlation")
+	deduplicator.setTTL(0)
+	deduplicator.release(owned, &amqp.RabbitMQMessage{})
+
+	deduplicator.setTTL(time.Minute)
+	if cached, _ := deduplicator.acquire(context.Background(), "correlation"); cached != nil {
+		t.Error("reply cached while deduplication is disabled")
+	}
+}
@@ -0,0 +1,76 @@
+package portals
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+func newInFlight() *inFlight {
+	return &inFlight{
+		idle: make(chan struct{}),
+	}
+}
+
+// acquire counts a new request being treated, unless requests are no longer accepted.
+func (requests *inFlight) acquire() bool {
+	requests.mutex.Lock()
+	defer requests.mutex.Unlock()
+
+	if requests.stopped {
+		return false
+	}
+
+	requests.count++
+	return true
+}
+
+// release ends a request acquired before.
+func (requests *inFlight) release() {
+	requests.mutex.Lock()
+	defer requests.mutex.Unlock()
+
+	requests.count--
+	if requests.stopped && requests.count == 0 {
+		close(requests.idle)
+	}
+}
+
+// stop refuses new requests and returns a channel closed once no request is being treated.
+func (requests *inFlight) stop() <-chan struct{} {
+	requests.mutex.Lock()
+	defer requests.mutex.Unlock()
+
+	if !requests.stopped {
+		requests.stopped = true
+		if requests.count == 0 {
+			close(requests.idle)
+		}
+	}
+
+	return requests.idle
+}
+
+func (requests *inFlight) size() int {
+	requests.mutex.Lock()
+	defer requests.mutex.Unlock()
+	return requests.count
+}
+
+// Stop refuses portal requests delivered from now on; kaelly-amqp cannot cancel
+// its consumers, so they are answered as failed instead of being treated.
+func (service *Impl) Stop() {
+	service.requests.stop()
+	log.Info().Msgf("Stopped consuming portal requests")
+}
+
+// Drain stops consuming and waits for the requests being treated, until ctx is done.
+func (service *Impl) Drain(ctx context.Context) error {
+	select {
+	case <-service.requests.stop():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %d request(s) still in flight", ctx.Err(), service.requests.size())
+	}
+}
@@ -0,0 +1,60 @@
+package portals
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+)
+
+func TestDrain(t *testing.T) {
+	service, fake, broker := newTestService(t)
+	fake.Script(mockportals.Behaviour{Latency: httpTimeout / 2})
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	}()
+
+	for service.requests.size() == 0 {
+		select {
+		case <-done:
+			t.Fatal("request treated before being drained")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := service.Drain(expired); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected drain to expire with a request in flight, got %v", err)
+	}
+
+	if err := service.Drain(context.Background()); err != nil {
+		t.Fatalf("expected drain to succeed, got %v", err)
+	}
+	<-done
+
+	requests := fake.Requests()
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	if fake.Requests() != requests {
+		t.Error("request delivered after stop reached dofus-portals")
+	}
+
+	replies := broker.Replies()
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 replies, got %d", len(replies))
+	}
+	if status := replies[0].Message.Status; status != amqp.RabbitMQMessage_SUCCESS {
+		t.Errorf("expected in-flight request to succeed, got %v", status)
+	}
+	if status := replies[1].Message.Status; status != amqp.RabbitMQMessage_FAILED {
+		t.Errorf("expected request delivered after stop to fail, got %v", status)
+	}
+}
@@ -0,0 +1,154 @@
+package portals
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/rs/zerolog/log"
+)
+
+func newEndpoints(urls []string, opts ...dofusportals.ClientOption) ([]*endpoint, error) {
+	endpoints := make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		client, err := dofusportals.NewClient(url, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints = append(endpoints, newEndpoint(url, client))
+	}
+
+	return endpoints, nil
+}
+
+func newEndpoint(url string, client dofusportals.ClientInterface) *endpoint {
+	insights.UpstreamEndpointHealthy.WithLabelValues(url).Set(1)
+	return &endpoint{
+		url:    url,
+		client: client,
+	}
+}
+
+// getURLs returns the primary URL followed by the fallback ones, in order.
+func getURLs(primary, fallbacks string) []string {
+	urls := []string{primary}
+	for _, url := range strings.Split(fallbacks, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
+// getEndpoints orders endpoints by preference: healthy ones first, keeping the
+// configured order. Unhealthy endpoints are still returned as a last resort.
+func (service *Impl) getEndpoints() []*endpoint {
+	healthy := make([]*endpoint, 0, len(service.endpoints))
+	unhealthy := make([]*endpoint, 0)
+	for _, endpoint := range service.endpoints {
+		if endpoint.isHealthy() {
+			healthy = append(healthy, endpoint)
+		} else {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// call tries every endpoint until one answers without a server-side failure.
+// Functional errors (4xx) are returned as is, without trying other endpoints.
+// Once the caller context is done, its error is returned without blaming any endpoint:
+// only a transport error or the per-attempt timeout counts as an endpoint failure.
+func (service *Impl) call(ctx context.Context,
+	request func(ctx context.Context, client dofusportals.ClientInterface) (*http.Response, error),
+) (*http.Response, context.CancelFunc, error) {
+	if !service.enabled.Load() {
+		return nil, nil, errDisabled
+	}
+
+	var lastErr error
+	for _, endpoint := range service.getEndpoints() {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, time.Duration(service.httpTimeout.Load()))
+		resp, err := request(attemptCtx, endpoint.client)
+		if err != nil {
+			cancel()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, nil, ctxErr
+			}
+
+			endpoint.failure()
+			insights.UpstreamRequests.WithLabelValues(endpoint.url, statusError).Inc()
+			log.Warn().Err(err).Str(constants.LogEndpoint, endpoint.url).
+				Msgf("Dofus Portals endpoint failed, trying next one")
+			lastErr = err
+			continue
+		}
+
+		insights.UpstreamRequests.WithLabelValues(endpoint.url, strconv.Itoa(resp.StatusCode)).Inc()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			resp.Body.Close()
+			cancel()
+			endpoint.failure()
+			log.Warn().Int(constants.LogStatusCode, resp.StatusCode).Str(constants.LogEndpoint, endpoint.url).
+				Msgf("Dofus Portals endpoint failed, trying next one")
+			lastErr = errStatusNotOK
+			continue
+		}
+
+		endpoint.success()
+		if endpoint == service.endpoints[0] {
+			log.Debug().Str(constants.LogEndpoint, endpoint.url).Msgf("Dofus Portals endpoint answered")
+		} else {
+			log.Info().Str(constants.LogEndpoint, endpoint.url).Msgf("Dofus Portals fallback endpoint answered")
+		}
+		return resp, cancel, nil
+	}
+
+	if lastErr == nil {
+		return nil, nil, errNoEndpoint
+	}
+
+	return nil, nil, fmt.Errorf("%w: %w", errUnavailable, lastErr)
+}
+
+func (endpoint *endpoint) isHealthy() bool {
+	endpoint.mutex.Lock()
+	defer endpoint.mutex.Unlock()
+	return time.Now().After(endpoint.unhealthyUntil)
+}
+
+func (endpoint *endpoint) success() {
+	endpoint.mutex.Lock()
+	defer endpoint.mutex.Unlock()
+	if endpoint.failures >= endpointFailureThreshold {
+		log.Info().Str(constants.LogEndpoint, endpoint.url).Msgf("Dofus Portals endpoint is healthy again")
+	}
+	endpoint.failures = 0
+	endpoint.unhealthyUntil = time.Time{}
+	insights.UpstreamEndpointHealthy.WithLabelValues(endpoint.url).Set(1)
+}
+
+func (endpoint *endpoint) failure() {
+	endpoint.mutex.Lock()
+	defer endpoint.mutex.Unlock()
+	endpoint.failures++
+	if endpoint.failures >= endpointFailureThreshold {
+		endpoint.unhealthyUntil = time.Now().Add(endpointCooldown)
+		insights.UpstreamEndpointHealthy.WithLabelValues(endpoint.url).Set(0)
+		log.Warn().Str(constants.LogEndpoint, endpoint.url).
+			Msgf("Dofus Portals endpoint considered unhealthy for %v", endpointCooldown)
+	}
+}
@@ -0,0 +1,132 @@
+package portals
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+)
+
+func TestGetURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		primary   string
+		fallbacks string
+		expected  []string
+	}{
+		{name: "no fallback", primary: "http://a", expected: []string{"http://a"}},
+		{name: "fallbacks", primary: "http://a", fallbacks: "http://b, http://c,,",
+			expected: []string{"http://a", "http://b", "http://c"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			urls := getURLs(test.primary, test.fallbacks)
+			if len(urls) != len(test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, urls)
+			}
+			for i := range urls {
+				if urls[i] != test.expected[i] {
+					t.Errorf("expected %v, got %v", test.expected, urls)
+				}
+			}
+		})
+	}
+}
+
+func TestFailover(t *testing.T) {
+	tests := []struct {
+		name          string
+		primary       mockportals.Behaviour
+		expectedError bool
+		expectedOrder string
+	}{
+		{name: "healthy primary", expectedOrder: "primary"},
+		{name: "server error on primary",
+			primary: mockportals.Behaviour{StatusCode: http.StatusInternalServerError}, expectedOrder: "fallback"},
+		{name: "timeout on primary",
+			primary: mockportals.Behaviour{Latency: 2 * httpTimeout}, expectedOrder: "fallback"},
+		{name: "functional error on primary", expectedError: true,
+			primary: mockportals.Behaviour{IntendedError: dofusportals.ServerNotFound}, expectedOrder: "primary"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, primary, _ := newTestService(t)
+			primary.Script(test.primary)
+			fallback := mockportals.New(token)
+			t.Cleanup(fallback.Close)
+			fallback.SetPortals(dofusportals.Portal{Server: "agride", Dimension: "enutrosor"})
+
+			endpoints, err := newEndpoints([]string{fallback.URL}, withTestCredentials())
+			if err != nil {
+				t.Fatalf("cannot build endpoints: %v", err)
+			}
+			service.endpoints = append(service.endpoints, endpoints...)
+
+			portals, err := service.getPortals(context.Background(), "agride")
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error: %v, got %v", test.expectedError, err)
+			}
+
+			// Primary fake returns two portals while fallback one returns only one.
+			expectedCount := map[string]int{"primary": 2, "fallback": 1}[test.expectedOrder]
+			if !test.expectedError && len(portals) != expectedCount {
+				t.Errorf("expected answer from %s endpoint, got %d portals", test.expectedOrder, len(portals))
+			}
+		})
+	}
+}
+
+func TestUnhealthyEndpointIsTriedLast(t *testing.T) {
+	service, primary, _ := newTestService(t)
+	primary.Script(mockportals.Behaviour{StatusCode: http.StatusBadGateway})
+
+	for range endpointFailureThreshold {
+		if _, err := service.getPortals(context.Background(), "agride"); err == nil {
+			t.Fatalf("expected primary endpoint to fail")
+		}
+	}
+
+	if service.endpoints[0].isHealthy() {
+		t.Errorf("expected endpoint to be unhealthy after %d failures", endpointFailureThreshold)
+	}
+
+	primary.Script(mockportals.Behaviour{})
+	if _, err := service.getPortals(context.Background(), "agride"); err != nil {
+		t.Fatalf("unhealthy endpoint should still be tried as last resort: %v", err)
+	}
+
+	if !service.endpoints[0].isHealthy() {
+		t.Errorf("expected endpoint to be healthy again after a success")
+	}
+}
+
+func TestCallerCancellationDoesNotBlameEndpoints(t *testing.T) {
+	service, primary, _ := newTestService(t)
+	primary.Script(mockportals.Behaviour{Latency: httpTimeout / 2})
+
+	for _, name := range []string{"cancelled", "cancelled during the request"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		if name == "cancelled" {
+			cancel()
+		} else {
+			time.AfterFunc(httpTimeout/10, cancel)
+		}
+
+		for range endpointFailureThreshold {
+			if _, err := service.getPortals(ctx, "agride"); !errors.Is(err, context.Canceled) {
+				t.Fatalf("%s: expected caller context error, got %v", name, err)
+			}
+		}
+		cancel()
+
+		if !service.endpoints[0].isHealthy() || service.endpoints[0].failures != 0 {
+			t.Errorf("%s: expected endpoint not to be blamed for the caller giving up", name)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package portals
+
+import (
+	"fmt"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+// route checks that a source can answer the request: accepted by its queue and knowing
+// the portals of its game, which is returned. dofus-portals being the only source so far,
+// requests are then always retrieved from it.
+func (service *Impl) route(binding configs.RequestBinding, message *amqp.RabbitMQMessage,
+) (amqp.Game, error) {
+	request := message.GetPortalPositionRequest()
+	game, err := service.getGame(message.GetGame(), request.GetServerId(), request.GetDimensionId())
+	if err != nil {
+		return game, err
+	}
+
+	for _, source := range constants.GetSources() {
+		if binding.Accepts(source.Name) && source.Supports(game) {
+			return game, nil
+		}
+	}
+
+	return game, fmt.Errorf("%w: %v", errNoSource, game)
+}
+
+// checkGame applies the game check of routed requests to the callers that are not bound
+// to a queue, such as the HTTP API, the fetch command and the poller: the server and the
+// dimension must belong to the same game, whose portals are known by a source.
+func (service *Impl) checkGame(serverID, dimensionID string) error {
+	game, err := service.getGame(amqp.Game_ANY_GAME, serverID, dimensionID)
+	if err != nil {
+		return err
+	}
+
+	for _, source := range constants.GetSources() {
+		if source.Supports(game) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %v", errNoSource, game)
+}
+
+// getGame returns the requested game, or the one of the server when the request
+// is not bound to a game. The server and the dimension must belong to this game.
+func (service *Impl) getGame(game amqp.Game, serverID, dimensionID string) (amqp.Game, error) {
+	if server, found := service.serverService.GetServer(serverID); found {
+		if !constants.MatchesGame(server.Game, game) {
+			return game, fmt.Errorf("%w: server %v belongs to %v", errGameMismatch, server.ID, server.Game)
+		}
+		if game == amqp.Game_ANY_GAME {
+			game = server.Game
+		}
+	}
+
+	if dimension, found := service.dimensionService.GetDimension(dimensionID); found {
+		if !constants.MatchesGame(dimension.Game, game) {
+			return game, fmt.Errorf("%w: dimension %v belongs to %v", errGameMismatch, dimension.ID, dimension.Game)
+		}
+	}
+
+	return game, nil
+}
@@ -0,0 +1,106 @@
+package portals
+
+import (
+	"errors"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		name          string
+		game          amqp.Game
+		serverID      string
+		dimensionID   string
+		binding       configs.RequestBinding
+		expectedError error
+	}{
+		{name: "any game", serverID: "shared"},
+		{name: "game of the server", serverID: "dofus"},
+		{name: "requested game", game: amqp.Game_DOFUS_GAME, serverID: "shared", dimensionID: "enu"},
+		{name: "unknown server", game: amqp.Game_DOFUS_GAME, serverID: "unknown"},
+		{name: "server of another game", game: amqp.Game_DOFUS_GAME, serverID: "touch",
+			expectedError: errGameMismatch},
+		{name: "dimension of another game", game: amqp.Game_DOFUS_GAME, serverID: "dofus", dimensionID: "touch",
+			expectedError: errGameMismatch},
+		{name: "game without source", serverID: "touch", expectedError: errNoSource},
+		{name: "source not accepted by the queue", serverID: "dofus",
+			binding: configs.RequestBinding{Sources: []string{"other"}}, expectedError: errNoSource},
+	}
+
+	service := newGameService(t)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := &amqp.RabbitMQMessage{
+				Type: amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST,
+				Game: test.game,
+				PortalPositionRequest: &amqp.PortalPositionRequest{
+					ServerId:    test.serverID,
+					DimensionId: test.dimensionID,
+				},
+			}
+
+			_, err := service.route(test.binding, message)
+			if test.expectedError == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if test.expectedError != nil && !errors.Is(err, test.expectedError) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCheckGame(t *testing.T) {
+	tests := []struct {
+		name          string
+		serverID      string
+		dimensionID   string
+		expectedError error
+	}{
+		{name: "server of any game", serverID: "shared", dimensionID: "touch"},
+		{name: "dimension of the game of the server", serverID: "dofus", dimensionID: "enu"},
+		{name: "unknown server", serverID: "unknown"},
+		{name: "dimension of another game", serverID: "dofus", dimensionID: "touch", expectedError: errGameMismatch},
+		{name: "game without source", serverID: "touch", expectedError: errNoSource},
+	}
+
+	service := newGameService(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := service.checkGame(test.serverID, test.dimensionID)
+			if test.expectedError == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if test.expectedError != nil && !errors.Is(err, test.expectedError) {
+				t.Fatalf("expected error %v, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func newGameService(t *testing.T) *Impl {
+	t.Helper()
+	serverService, err := servers.New(references.Servers{
+		{ID: "shared", DofusPortalsID: "shared"},
+		{ID: "dofus", DofusPortalsID: "dofus", Game: amqp.Game_DOFUS_GAME},
+		{ID: "touch", DofusPortalsID: "touch", Game: amqp.Game_DOFUS_TOUCH},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dimensionService, err := dimensions.New(references.Dimensions{
+		{ID: "enu", DofusPortalsID: "enutrosor"},
+		{ID: "touch", DofusPortalsID: "touch", Game: amqp.Game_DOFUS_TOUCH},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Impl{serverService: serverService, dimensionService: dimensionService}
+}
@@ -3,6 +3,7 @@ package portals
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 
@@ -11,64 +12,108 @@ import (
 	"github.com/kaellybot/kaelly-portals/models/mappers"
 	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
 	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
 	"github.com/kaellybot/kaelly-portals/services/dimensions"
 	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
 	"github.com/kaellybot/kaelly-portals/services/subareas"
 	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/kaellybot/kaelly-portals/utils/recorders"
 	"github.com/kaellybot/kaelly-portals/utils/replies"
-	"github.com/oapi-codegen/oapi-codegen/v2/pkg/securityprovider"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 )
 
-func New(broker amqp.MessageBroker, serverService servers.Service,
+func New(broker amqp.MessageBroker, config configs.Config, serverService servers.Service,
 	dimensionService dimensions.Service, areaService areas.Service,
-	subAreaService subareas.Service, transportService transports.Service) (*Impl, error) {
-	apiKeyProvIDer, err := securityprovider.
-		NewSecurityProviderApiKey(httpHeader, httpAPIToken, viper.GetString(constants.DofusPortalsToken))
+	subAreaService subareas.Service, transportService transports.Service,
+	deadLetterService deadletters.Service, confidenceService confidences.Service,
+	snapshotService snapshots.Service, boundsService bounds.Service) (*Impl, error) {
+	httpClient, err := recorders.New(config.HTTPMode, config.HTTPFixturesDir, &http.Client{})
 	if err != nil {
 		return nil, err
 	}
 
-	dofusPortalsClient, err := dofusportals.NewClient(
-		constants.DofusPortalsURL,
-		dofusportals.WithRequestEditorFn(apiKeyProvIDer.Intercept),
-	)
+	credentials := newCredentials(insights.NewTracingDoer(httpClient),
+		tokenSource{
+			value: config.DofusPortalsToken,
+			file:  config.DofusPortalsTokenFile,
+		},
+		tokenSource{
+			value: config.DofusPortalsSecondaryToken,
+			file:  config.DofusPortalsSecondaryTokenFile,
+		})
+
+	urls := getURLs(config.DofusPortalsURL, config.DofusPortalsFallbackURLs)
+	endpoints, err := newEndpoints(urls, dofusportals.WithHTTPClient(credentials))
 	if err != nil {
 		return nil, err
 	}
 
-	return &Impl{
-		serverService:      serverService,
-		dimensionService:   dimensionService,
-		areaService:        areaService,
-		subAreaService:     subAreaService,
-		transportService:   transportService,
-		broker:             broker,
-		dofusPortalsClient: dofusPortalsClient,
-		httpTimeout:        viper.GetDuration(constants.DofusPortalsTimeout),
-	}, nil
+	service := Impl{
+		serverService:     serverService,
+		dimensionService:  dimensionService,
+		areaService:       areaService,
+		subAreaService:    subAreaService,
+		transportService:  transportService,
+		deadLetterService: deadLetterService,
+		confidenceService: confidenceService,
+		snapshotService:   snapshotService,
+		boundsService:     boundsService,
+		broker:            broker,
+		endpoints:         endpoints,
+		deduplicator:      newDeduplicator(config.DeduplicationTTL),
+		userQuotas:        newQuotas(config.QuotaUserLimit, config.QuotaWindow),
+		quotaMode:         config.QuotaMode,
+		suspiciousMode:    config.SuspiciousPositionMode,
+		cache:             newPositionCache(),
+		requests:          newInFlight(),
+		queues:            newQueues(config.RequestBindings),
+	}
+	service.Reload(config.Runtime())
+
+	return &service, nil
 }
 
-func GetBinding() amqp.Binding {
-	return amqp.Binding{
-		Exchange:   amqp.ExchangeRequest,
-		RoutingKey: requestsRoutingkey,
-		Queue:      requestQueueName,
+func (service *Impl) Consume() {
+	for _, queue := range service.queues {
+		log.Info().
+			Str(constants.LogQueue, queue.binding.Queue).
+			Int(constants.LogWorkers, queue.binding.Workers).
+			Msgf("Consuming portal requests...")
+		service.broker.Consume(queue.binding.Queue, service.consumer(queue))
 	}
 }
 
-func (service *Impl) Consume() {
-	log.Info().Msgf("Consuming portal requests...")
-	service.broker.Consume(requestQueueName, service.consume)
+// Reload applies the runtime configuration, without interrupting requests being treated.
+// DOFUS_PORTALS_ENABLED is only applied when it changes, to keep admin toggles across unrelated reloads.
+func (service *Impl) Reload(runtime configs.Runtime) {
+	service.httpTimeout.Store(int64(runtime.HTTPTimeout))
+	service.deduplicator.setTTL(runtime.DeduplicationTTL)
+	enabled := runtime.DofusPortalsEnabled
+	if previous := service.configuredEnabled.Swap(&enabled); previous == nil || *previous != enabled {
+		service.SetEnabled(enabled)
+	}
 }
 
-func (service *Impl) consume(ctx amqp.Context, message *amqp.RabbitMQMessage) {
+// consume treats a portal request of a game, positions being cached per game.
+func (service *Impl) consume(ctx amqp.Context, message *amqp.RabbitMQMessage, game amqp.Game) {
+	var err error
+	spanCtx, span := insights.StartSpan(ctx, "portals.consume",
+		insights.AttributeCorrelationID.String(ctx.CorrelationID))
+	defer func() { insights.EndSpan(span, err) }()
+	ctx.Context = spanCtx
+
 	if !isValidPortalRequest(message) {
+		err = errInvalidMessage
 		log.Error().
-			Err(errInvalidMessage).
+			Err(err).
 			Str(constants.LogCorrelationID, ctx.CorrelationID).
 			Msgf("Cannot treat request, returning failed message")
+		service.deadLetterService.Record(ctx, message, err, true)
 		replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
 			message.Language)
 		return
@@ -76,6 +121,36 @@ func (service *Impl) consume(ctx amqp.Context, message *amqp.RabbitMQMessage) {
 
 	serverID := message.GetPortalPositionRequest().GetServerId()
 	dimensionID := message.GetPortalPositionRequest().GetDimensionId()
+	span.SetAttributes(insights.AttributeServerID.String(serverID),
+		insights.AttributeDimensionID.String(dimensionID))
+
+	reply, owned := service.deduplicator.acquire(ctx, ctx.CorrelationID)
+	if reply != nil {
+		log.Info().
+			Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Msgf("Request already answered, replying again with the same answer")
+		insights.DuplicateRequests.Inc()
+		replies.SucceededAnswer(ctx, service.broker, reply)
+		return
+	}
+
+	if service.deadLetterService.IsQuarantined(ctx.CorrelationID) {
+		err = errQuarantined
+		log.Warn().
+			Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Msgf("Request quarantined, returning failed message until requeued")
+		service.deduplicator.release(owned, nil)
+		replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
+			message.Language)
+		return
+	}
+
+	if !service.userQuotas.allow(message.UserID) {
+		err = errRateLimited
+		service.deduplicator.release(owned, nil)
+		service.answerRateLimited(ctx, message, game, serverID, dimensionID)
+		return
+	}
 
 	log.Info().
 		Str(constants.LogCorrelationID, ctx.CorrelationID).
@@ -83,51 +158,142 @@ func (service *Impl) consume(ctx amqp.Context, message *amqp.RabbitMQMessage) {
 		Str(constants.LogDimensionID, dimensionID).
 		Msgf("Treating request")
 
-	dofusPortalsServerID := service.getDofusPortalsServerID(serverID)
+	var portals []*amqp.PortalPositionAnswer_PortalPosition
+	portals, err = service.getMappedPortals(ctx, serverID, dimensionID)
+	if err != nil {
+		log.Error().Err(err).
+			Str(constants.LogCorrelationID, ctx.CorrelationID).
+			Str(constants.LogServerID, serverID).
+			Str(constants.LogDimensionID, dimensionID).
+			Msgf("Returning failed message")
+		if isRequeueable(err) {
+			service.deadLetterService.Record(ctx, message, err, false)
+		}
+		service.deduplicator.release(owned, nil)
+		replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
+			message.Language)
+		return
+	}
 
-	portals := make([]*amqp.PortalPositionAnswer_PortalPosition, 0)
-	if dimensionID != "" {
-		dofusPortalsDimensionID := service.getDofusPortalsDimensionID(dimensionID)
-		dofusPortal, err := service.getPortal(ctx, dofusPortalsServerID, dofusPortalsDimensionID)
-		if err != nil {
-			log.Error().Err(err).
+	service.deadLetterService.Resolve(ctx.CorrelationID)
+	service.cache.store(game, serverID, dimensionID, portals)
+	response := mappers.MapPortalAnswer(portals, message.Language)
+	service.deduplicator.release(owned, response)
+	replies.SucceededAnswer(ctx, service.broker, response)
+}
+
+// answerRateLimited answers from the cache if allowed and filled, otherwise fails.
+func (service *Impl) answerRateLimited(ctx amqp.Context, message *amqp.RabbitMQMessage, game amqp.Game,
+	serverID, dimensionID string) {
+	if service.quotaMode == constants.QuotaModeCache {
+		if portals, found := service.cache.get(game, serverID, dimensionID); found {
+			log.Warn().
 				Str(constants.LogCorrelationID, ctx.CorrelationID).
-				Str(constants.LogServerID, serverID).
-				Str(constants.LogDimensionID, dimensionID).
-				Msgf("Returning failed message")
-			replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
-				message.Language)
+				Str(constants.LogUserID, message.UserID).
+				Msgf("User over quota, answering from cache")
+			insights.RateLimitedRequests.WithLabelValues(constants.QuotaModeCache).Inc()
+			replies.SucceededAnswer(ctx, service.broker, mappers.MapPortalAnswer(portals, message.Language))
 			return
 		}
+	}
 
-		portals = append(portals, mappers.MapPortal(dofusPortal, service.serverService, service.dimensionService,
-			service.areaService, service.subAreaService, service.transportService))
-	} else {
-		dofusPortals, err := service.getPortals(ctx, dofusPortalsServerID)
+	log.Warn().
+		Err(errRateLimited).
+		Str(constants.LogCorrelationID, ctx.CorrelationID).
+		Str(constants.LogUserID, message.UserID).
+		Msgf("User over quota, returning failed message")
+	insights.RateLimitedRequests.WithLabelValues(constants.QuotaModeReject).Inc()
+	replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
+		message.Language)
+}
+
+// GetPortals retrieves the portal positions of a server based on internal IDs.
+// If dimensionID is empty, every dimension of the server is returned. The game
+// is checked as for routed requests.
+func (service *Impl) GetPortals(ctx context.Context, serverID, dimensionID string,
+) ([]*amqp.PortalPositionAnswer_PortalPosition, error) {
+	if err := service.checkGame(serverID, dimensionID); err != nil {
+		return nil, err
+	}
+
+	return service.getMappedPortals(ctx, serverID, dimensionID)
+}
+
+// getMappedPortals retrieves the portals of a server, or of one of its dimensions, mapped
+// to internal IDs; the game of the request is expected to be checked already.
+func (service *Impl) getMappedPortals(ctx context.Context, serverID, dimensionID string,
+) ([]*amqp.PortalPositionAnswer_PortalPosition, error) {
+	dofusPortalsServerID, dofusPortalsDimensionID := service.getDofusPortalsIDs(ctx, serverID, dimensionID)
+
+	var dofusPortals []dofusportals.Portal
+	if dimensionID != "" {
+		dofusPortal, err := service.getPortal(ctx, dofusPortalsServerID, dofusPortalsDimensionID)
 		if err != nil {
-			log.Error().Err(err).
-				Str(constants.LogCorrelationID, ctx.CorrelationID).
-				Str(constants.LogServerID, serverID).
-				Msgf("Returning failed message")
-			replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
-				message.Language)
-			return
+			return nil, err
 		}
 
-		for _, dofusPortal := range dofusPortals {
-			portals = append(portals, mappers.MapPortal(dofusPortal, service.serverService, service.dimensionService,
-				service.areaService, service.subAreaService, service.transportService))
+		dofusPortals = []dofusportals.Portal{dofusPortal}
+	} else {
+		var err error
+		dofusPortals, err = service.getPortals(ctx, dofusPortalsServerID)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	response := mappers.MapPortalAnswer(portals, message.Language)
-	replies.SucceededAnswer(ctx, service.broker, response)
+	portals := service.mapPortals(ctx, dofusPortals)
+	service.confidenceService.Observe(portals)
+	service.snapshotService.Record(ctx, portals)
+	return portals, nil
+}
+
+// GetDofusPortalsServers retrieves the raw server catalog exposed by dofus-portals.
+func (service *Impl) GetDofusPortalsServers(ctx context.Context) ([]dofusportals.Server, error) {
+	return fetch[[]dofusportals.Server](ctx, service,
+		func(ctx context.Context, client dofusportals.ClientInterface) (*http.Response, error) {
+			return client.GetExternalV1Servers(ctx)
+		})
+}
+
+// GetDofusPortalsDimensions retrieves the raw dimension catalog exposed by dofus-portals.
+func (service *Impl) GetDofusPortalsDimensions(ctx context.Context) ([]dofusportals.Dimension, error) {
+	return fetch[[]dofusportals.Dimension](ctx, service,
+		func(ctx context.Context, client dofusportals.ClientInterface) (*http.Response, error) {
+			return client.GetExternalV1Dimensions(ctx)
+		})
+}
+
+// GetDofusPortalsPortals retrieves the raw portals of a server, identified by its dofus-portals ID.
+func (service *Impl) GetDofusPortalsPortals(ctx context.Context, dofusPortalsServerID string,
+) ([]dofusportals.Portal, error) {
+	return service.getPortals(ctx, dofusPortalsServerID)
+}
+
+// isRequeueable tells if a failure is specific to the request, so that treating it again
+// once the cause is fixed makes sense; a disabled or unavailable source fails every request alike.
+func isRequeueable(err error) bool {
+	return !errors.Is(err, errDisabled) && !errors.Is(err, errNoEndpoint) && !errors.Is(err, errUnavailable)
 }
 
 func isValidPortalRequest(message *amqp.RabbitMQMessage) bool {
 	return message.Type == amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST && message.GetPortalPositionRequest() != nil
 }
 
+func (service *Impl) getDofusPortalsIDs(ctx context.Context, serverID, dimensionID string,
+) (dofusPortalsServerID, dofusPortalsDimensionID string) {
+	_, span := insights.StartSpan(ctx, "references.translate",
+		insights.AttributeServerID.String(serverID),
+		insights.AttributeDimensionID.String(dimensionID))
+	defer span.End()
+
+	dofusPortalsServerID = service.getDofusPortalsServerID(serverID)
+	if dimensionID != "" {
+		dofusPortalsDimensionID = service.getDofusPortalsDimensionID(dimensionID)
+	}
+
+	return dofusPortalsServerID, dofusPortalsDimensionID
+}
+
 func (service *Impl) getDofusPortalsServerID(serverID string) string {
 	server, found := service.serverService.GetServer(serverID)
 	if !found {
@@ -148,54 +314,77 @@ func (service *Impl) getDofusPortalsDimensionID(dimensionID string) string {
 	return dimension.DofusPortalsID
 }
 
-func (service *Impl) getPortals(ctx context.Context, server string) ([]dofusportals.Portal, error) {
-	ctx, cancel := context.WithTimeout(ctx, service.httpTimeout)
-	defer cancel()
-	resp, err := service.dofusPortalsClient.GetExternalV1ServersServerIdPortals(ctx, server)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (service *Impl) mapPortals(ctx context.Context, dofusPortals []dofusportals.Portal,
+) []*amqp.PortalPositionAnswer_PortalPosition {
+	_, span := insights.StartSpan(ctx, "references.map")
+	defer span.End()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, errStatusNotOK
+	portals := make([]*amqp.PortalPositionAnswer_PortalPosition, 0, len(dofusPortals))
+	for _, dofusPortal := range dofusPortals {
+		portal := mappers.MapPortal(dofusPortal, service.serverService, service.dimensionService,
+			service.areaService, service.subAreaService, service.transportService)
+		service.checkBounds(portal)
+		portals = append(portals, portal)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	return portals
+}
+
+// checkBounds counts suspicious positions and, in drop mode, removes them
+// while keeping the portal, as if its position was unknown.
+func (service *Impl) checkBounds(portal *amqp.PortalPositionAnswer_PortalPosition) {
+	err := service.boundsService.Validate(portal)
+	if err == nil {
+		return
 	}
 
-	var portals []dofusportals.Portal
-	if err = json.Unmarshal(body, &portals); err != nil {
-		return nil, err
+	log.Warn().Err(err).
+		Str(constants.LogServerID, portal.GetServerId()).
+		Str(constants.LogDimensionID, portal.GetDimensionId()).
+		Str(constants.LogMode, service.suspiciousMode).
+		Msgf("Suspicious portal position")
+	insights.SuspiciousPositions.WithLabelValues(service.suspiciousMode).Inc()
+	if service.suspiciousMode == constants.SuspiciousModeDrop {
+		portal.Position = nil
 	}
+}
 
-	return portals, nil
+func (service *Impl) getPortals(ctx context.Context, server string) ([]dofusportals.Portal, error) {
+	return fetch[[]dofusportals.Portal](ctx, service,
+		func(ctx context.Context, client dofusportals.ClientInterface) (*http.Response, error) {
+			return client.GetExternalV1ServersServerIdPortals(ctx, server)
+		})
 }
 
 func (service *Impl) getPortal(ctx context.Context, server, dimension string) (dofusportals.Portal, error) {
-	ctx, cancel := context.WithTimeout(ctx, service.httpTimeout)
-	defer cancel()
-	resp, err := service.dofusPortalsClient.GetExternalV1ServersServerIdPortalsDimensionId(ctx, server, dimension)
+	return fetch[dofusportals.Portal](ctx, service,
+		func(ctx context.Context, client dofusportals.ClientInterface) (*http.Response, error) {
+			return client.GetExternalV1ServersServerIdPortalsDimensionId(ctx, server, dimension)
+		})
+}
+
+func fetch[T any](ctx context.Context, service *Impl,
+	request func(ctx context.Context, client dofusportals.ClientInterface) (*http.Response, error)) (T, error) {
+	var result T
+	resp, cancel, err := service.call(ctx, request)
 	if err != nil {
-		return dofusportals.Portal{}, err
+		return result, err
 	}
+	defer cancel()
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return dofusportals.Portal{}, errStatusNotOK
+		return result, errStatusNotOK
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return dofusportals.Portal{}, err
+		return result, err
 	}
 
-	var portal dofusportals.Portal
-	if err = json.Unmarshal(body, &portal); err != nil {
-		return dofusportals.Portal{}, err
+	if err = json.Unmarshal(body, &result); err != nil {
+		return result, err
 	}
 
-	return portal, nil
+	return result, nil
 }
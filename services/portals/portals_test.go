@@ -0,0 +1,452 @@
+package portals
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/brokers"
+	mockdeadletters "github.com/kaellybot/kaelly-portals/mocks/deadletters"
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	mocksnapshots "github.com/kaellybot/kaelly-portals/mocks/snapshots"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	token       = "token"
+	httpTimeout = 100 * time.Millisecond
+	maxAttempts = 2
+
+	requestQueueName = "portals-requests"
+)
+
+func newTestService(t *testing.T) (*Impl, *mockportals.Server, *brokers.Broker) {
+	t.Helper()
+	service, fake, broker, _ := newTestServiceWithDeadLetters(t)
+	return service, fake, broker
+}
+
+func newTestServiceWithDeadLetters(t *testing.T,
+) (*Impl, *mockportals.Server, *brokers.Broker, *deadletters.Impl) {
+	t.Helper()
+	fake := mockportals.New(token)
+	t.Cleanup(fake.Close)
+
+	remainingUses := float32(42)
+	fake.SetPortals(
+		dofusportals.Portal{Server: "agride", Dimension: "enutrosor", RemainingUses: &remainingUses,
+			Position: &dofusportals.Position{X: 5, Y: -18, Transport: &dofusportals.Transport{
+				Area: "astrub", SubArea: "cite_astrub", Type: dofusportals.Zaap, X: 5, Y: -18}}},
+		dofusportals.Portal{Server: "agride", Dimension: "srambad"},
+	)
+
+	client, err := dofusportals.NewClient(fake.URL, withTestCredentials())
+	if err != nil {
+		t.Fatalf("cannot build client: %v", err)
+	}
+
+	refs := references.New(
+		references.Servers{{ID: "1", DofusPortalsID: "agride"}},
+		references.Dimensions{{ID: "enu", DofusPortalsID: "enutrosor"}, {ID: "sram", DofusPortalsID: "srambad"}},
+		references.Areas{{ID: "area-astrub", DofusPortalsID: "astrub"}},
+		references.SubAreas{{ID: "subarea-astrub", DofusPortalsID: "cite_astrub"}},
+		references.TransportTypes{{ID: "transport-zaap", DofusPortalsID: "zaap"}},
+	)
+
+	deadLetterService, err := deadletters.New(configs.Config{
+		DeadLetterMaxAttempts: maxAttempts,
+		DeadLetterRetention:   time.Hour,
+		DeadLetterMaxEntries:  maxAttempts,
+	}, mockdeadletters.New())
+	if err != nil {
+		t.Fatalf("cannot build dead letter service: %v", err)
+	}
+
+	boundsService := newTestBounds(t)
+	broker := brokers.New()
+	service := Impl{
+		endpoints:         []*endpoint{newEndpoint(fake.URL, client)},
+		broker:            broker,
+		serverService:     refs.Servers,
+		dimensionService:  refs.Dimensions,
+		areaService:       refs.Areas,
+		subAreaService:    refs.SubAreas,
+		transportService:  refs.Transports,
+		deadLetterService: deadLetterService,
+		confidenceService: confidences.New(24*time.Hour, boundsService),
+		snapshotService:   snapshots.New(mocksnapshots.New()),
+		boundsService:     boundsService,
+		deduplicator:      newDeduplicator(time.Minute),
+		userQuotas:        newQuotas(0, time.Minute),
+		quotaMode:         constants.QuotaModeCache,
+		suspiciousMode:    constants.SuspiciousModeAnnotate,
+		cache:             newPositionCache(),
+		requests:          newInFlight(),
+		queues:            newQueues([]configs.RequestBinding{{Queue: requestQueueName}}),
+	}
+	service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DeduplicationTTL: time.Minute, DofusPortalsEnabled: true})
+
+	return &service, fake, broker, deadLetterService
+}
+
+// newTestBounds knows the bounds of the sub-area holding the test portal position.
+func newTestBounds(t *testing.T) *bounds.Impl {
+	t.Helper()
+	boundsService, err := bounds.New(references.MapBounds{
+		{AreaID: "area-astrub", SubAreaID: "subarea-astrub", MinX: 0, MaxX: 10, MinY: -20, MaxY: -10},
+	})
+	if err != nil {
+		t.Fatalf("cannot build bounds service: %v", err)
+	}
+	return boundsService
+}
+
+func TestConsume(t *testing.T) {
+	tests := []struct {
+		name           string
+		message        *amqp.RabbitMQMessage
+		behaviour      mockportals.Behaviour
+		expectedStatus amqp.RabbitMQMessage_Status
+		expectedCount  int
+	}{
+		{
+			name:           "wrong type",
+			message:        &amqp.RabbitMQMessage{Type: amqp.RabbitMQMessage_ABOUT_REQUEST},
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+		{
+			name:           "missing request",
+			message:        &amqp.RabbitMQMessage{Type: amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST},
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+		{
+			name:           "every dimension",
+			message:        portalRequest("1", ""),
+			expectedStatus: amqp.RabbitMQMessage_SUCCESS,
+			expectedCount:  2,
+		},
+		{
+			name:           "one dimension",
+			message:        portalRequest("1", "enu"),
+			expectedStatus: amqp.RabbitMQMessage_SUCCESS,
+			expectedCount:  1,
+		},
+		{
+			name:           "unknown server",
+			message:        portalRequest("unknown", ""),
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+		{
+			name:           "intended error",
+			message:        portalRequest("1", ""),
+			behaviour:      mockportals.Behaviour{IntendedError: dofusportals.TokenNotFound},
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+		{
+			name:           "unexpected error",
+			message:        portalRequest("1", "enu"),
+			behaviour:      mockportals.Behaviour{StatusCode: http.StatusInternalServerError},
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+		{
+			name:           "malformed payload",
+			message:        portalRequest("1", ""),
+			behaviour:      mockportals.Behaviour{Malformed: true},
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+		{
+			name:           "timeout",
+			message:        portalRequest("1", ""),
+			behaviour:      mockportals.Behaviour{Latency: 2 * httpTimeout},
+			expectedStatus: amqp.RabbitMQMessage_FAILED,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, fake, broker := newTestService(t)
+			fake.Script(test.behaviour)
+			service.Consume()
+
+			ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+			if !broker.Deliver(requestQueueName, ctx, test.message) {
+				t.Fatalf("no consumer registered on %s", requestQueueName)
+			}
+
+			replies := broker.Replies()
+			if len(replies) != 1 {
+				t.Fatalf("expected 1 reply, got %d", len(replies))
+			}
+
+			reply := replies[0]
+			if reply.CorrelationID != ctx.CorrelationID || reply.ReplyTo != ctx.ReplyTo {
+				t.Errorf("reply not addressed to the requester: %+v", reply)
+			}
+			if reply.Message.Type != amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER {
+				t.Errorf("expected answer type, got %v", reply.Message.Type)
+			}
+			if reply.Message.Status != test.expectedStatus {
+				t.Errorf("expected status %v, got %v", test.expectedStatus, reply.Message.Status)
+			}
+			if count := len(reply.Message.GetPortalPositionAnswer().GetPositions()); count != test.expectedCount {
+				t.Errorf("expected %d positions, got %d", test.expectedCount, count)
+			}
+		})
+	}
+}
+
+func TestGetPortals(t *testing.T) {
+	tests := []struct {
+		name          string
+		server        string
+		behaviour     mockportals.Behaviour
+		expectedError bool
+		expectedCount int
+	}{
+		{name: "known server", server: "agride", expectedCount: 2},
+		{name: "unknown server", server: "unknown", expectedError: true},
+		{name: "intended error", server: "agride", expectedError: true,
+			behaviour: mockportals.Behaviour{IntendedError: dofusportals.ServerNotFound}},
+		{name: "malformed payload", server: "agride", expectedError: true,
+			behaviour: mockportals.Behaviour{Malformed: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, fake, _ := newTestService(t)
+			fake.Script(test.behaviour)
+
+			portals, err := service.getPortals(context.Background(), test.server)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error: %v, got %v", test.expectedError, err)
+			}
+			if len(portals) != test.expectedCount {
+				t.Errorf("expected %d portals, got %d", test.expectedCount, len(portals))
+			}
+		})
+	}
+}
+
+func TestGetPortal(t *testing.T) {
+	tests := []struct {
+		name              string
+		server            string
+		dimension         string
+		behaviour         mockportals.Behaviour
+		expectedError     bool
+		expectedDimension string
+	}{
+		{name: "known dimension", server: "agride", dimension: "enutrosor", expectedDimension: "enutrosor"},
+		{name: "unknown dimension", server: "agride", dimension: "unknown", expectedError: true},
+		{name: "unexpected error", server: "agride", dimension: "enutrosor", expectedError: true,
+			behaviour: mockportals.Behaviour{StatusCode: http.StatusInternalServerError}},
+		{name: "timeout", server: "agride", dimension: "enutrosor", expectedError: true,
+			behaviour: mockportals.Behaviour{Latency: 2 * httpTimeout}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, fake, _ := newTestService(t)
+			fake.Script(test.behaviour)
+
+			portal, err := service.getPortal(context.Background(), test.server, test.dimension)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error: %v, got %v", test.expectedError, err)
+			}
+			if portal.Dimension != test.expectedDimension {
+				t.Errorf("expected dimension %q, got %q", test.expectedDimension, portal.Dimension)
+			}
+		})
+	}
+}
+
+func withTestCredentials() dofusportals.ClientOption {
+	return dofusportals.WithHTTPClient(newCredentials(&http.Client{}, tokenSource{value: token}, tokenSource{}))
+}
+
+func portalRequest(serverID, dimensionID string) *amqp.RabbitMQMessage {
+	return &amqp.RabbitMQMessage{
+		Type: amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST,
+		PortalPositionRequest: &amqp.PortalPositionRequest{
+			ServerId:    serverID,
+			DimensionId: dimensionID,
+		},
+	}
+}
+
+func TestDisabledSource(t *testing.T) {
+	service, _, _ := newTestService(t)
+	service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DeduplicationTTL: time.Minute, DofusPortalsEnabled: false})
+
+	if _, err := service.GetPortals(context.Background(), "1", ""); err == nil {
+		t.Errorf("expected disabled source to fail")
+	}
+
+	service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DeduplicationTTL: time.Minute, DofusPortalsEnabled: true})
+	if _, err := service.GetPortals(context.Background(), "1", ""); err != nil {
+		t.Errorf("expected enabled source to succeed, got %v", err)
+	}
+}
+
+func TestAdminToggleSurvivesReload(t *testing.T) {
+	service, _, _ := newTestService(t)
+	service.SetEnabled(false)
+
+	service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DeduplicationTTL: time.Minute, DofusPortalsEnabled: true})
+	if service.GetStats().SourceEnabled {
+		t.Errorf("expected admin toggle to survive a reload with an unchanged configuration")
+	}
+
+	service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DeduplicationTTL: time.Minute, DofusPortalsEnabled: false})
+	service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DeduplicationTTL: time.Minute, DofusPortalsEnabled: true})
+	if !service.GetStats().SourceEnabled {
+		t.Errorf("expected a configuration change to override the admin toggle")
+	}
+}
+
+func TestConsumeTraces(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	service, _, broker := newTestService(t)
+	service.Consume()
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := amqp.Context{
+		Context:       trace.ContextWithRemoteSpanContext(context.Background(), parent),
+		CorrelationID: "correlation",
+		ReplyTo:       "reply",
+	}
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range exporter.GetSpans().Snapshots() {
+		spans[span.Name()] = span
+	}
+
+	consume, found := spans["portals.consume"]
+	if !found {
+		t.Fatalf("consume span not recorded: %v", spans)
+	}
+	if consume.Parent().SpanID() != parent.SpanID() {
+		t.Errorf("consume span not attached to the propagated context")
+	}
+
+	for _, name := range []string{"references.translate", "references.map", "amqp.reply"} {
+		span, found := spans[name]
+		if !found {
+			t.Errorf("%s span not recorded", name)
+			continue
+		}
+		if span.Parent().SpanID() != consume.SpanContext().SpanID() {
+			t.Errorf("%s span not attached to the consume span", name)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	fake := mockportals.New(token)
+	t.Cleanup(fake.Close)
+	fake.SetPortals(dofusportals.Portal{Server: "agride", Dimension: "enutrosor"})
+	refs := references.New(references.Servers{}, references.Dimensions{}, references.Areas{},
+		references.SubAreas{}, references.TransportTypes{})
+	config := configs.Config{
+		DofusPortalsEnabled:   true,
+		DofusPortalsURL:       fake.URL,
+		DofusPortalsToken:     token,
+		HTTPTimeout:           httpTimeout,
+		HTTPMode:              constants.HTTPModeLive,
+		DeadLetterMaxAttempts: 1,
+		DeadLetterRetention:   time.Hour,
+		DeadLetterMaxEntries:  1,
+		QuotaUserLimit:        1,
+		QuotaWindow:           time.Minute,
+		QuotaMode:             constants.QuotaModeCache,
+		RequestBindings:       []configs.RequestBinding{{Queue: requestQueueName, RoutingKey: "requests.portals"}},
+	}
+	deadLetterService, err := deadletters.New(config, mockdeadletters.New())
+	if err != nil {
+		t.Fatalf("cannot build dead letter service: %v", err)
+	}
+
+	broker := brokers.New()
+	service, err := New(broker, config,
+		refs.Servers, refs.Dimensions, refs.Areas, refs.SubAreas, refs.Transports, deadLetterService,
+		confidences.New(24*time.Hour, newTestBounds(t)), snapshots.New(mocksnapshots.New()), newTestBounds(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service.Consume()
+
+	message := &amqp.RabbitMQMessage{
+		Type:                  amqp.RabbitMQMessage_PORTAL_POSITION_REQUEST,
+		UserID:                "user",
+		PortalPositionRequest: &amqp.PortalPositionRequest{ServerId: "agride"},
+	}
+	deliver(t, broker, "first", message)
+	deliver(t, broker, "second", message)
+
+	replies := broker.Replies()
+	if len(replies) != 2 {
+		t.Fatalf("expected 2 replies, got %d", len(replies))
+	}
+	for _, reply := range replies {
+		if reply.Message.Status != amqp.RabbitMQMessage_SUCCESS {
+			t.Errorf("expected request over quota to be answered from cache, got %v", reply.Message.Status)
+		}
+	}
+	if requests := fake.Requests(); requests != 1 {
+		t.Errorf("expected 1 upstream request, got %d", requests)
+	}
+}
+
+func TestSuspiciousPositions(t *testing.T) {
+	tests := []struct {
+		name             string
+		mode             string
+		expectedPosition bool
+	}{
+		{name: "annotate", mode: constants.SuspiciousModeAnnotate, expectedPosition: true},
+		{name: "drop", mode: constants.SuspiciousModeDrop, expectedPosition: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, fake, _ := newTestService(t)
+			service.suspiciousMode = test.mode
+			fake.SetPortals(dofusportals.Portal{Server: "agride", Dimension: "enutrosor",
+				Position: &dofusportals.Position{X: -999, Y: 12}})
+
+			portals, err := service.GetPortals(context.Background(), "1", "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(portals) != 1 || portals[0].GetDimensionId() != "enu" {
+				t.Fatalf("expected portal to be kept, got %v", portals)
+			}
+			if hasPosition := portals[0].GetPosition() != nil; hasPosition != test.expectedPosition {
+				t.Errorf("expected position kept: %v, got %v", test.expectedPosition, hasPosition)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package portals
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+func TestConsumeQuarantinesInvalidMessage(t *testing.T) {
+	service, _, broker, deadLetterService := newTestServiceWithDeadLetters(t)
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	broker.Deliver(requestQueueName, ctx, &amqp.RabbitMQMessage{Type: amqp.RabbitMQMessage_ABOUT_REQUEST})
+
+	deadLetters := deadLetterService.GetDeadLetters()
+	if len(deadLetters) != 1 || !deadLetters[0].Quarantined || deadLetters[0].Attempts != 1 {
+		t.Fatalf("invalid message not quarantined: %+v", deadLetters)
+	}
+}
+
+func TestConsumeQuarantinesRepeatedFailures(t *testing.T) {
+	service, fake, broker, deadLetterService := newTestServiceWithDeadLetters(t)
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	fake.Script(mockportals.Behaviour{Malformed: true})
+	for range maxAttempts {
+		broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	}
+
+	if !deadLetterService.IsQuarantined(ctx.CorrelationID) {
+		t.Fatalf("request not quarantined after %d failures", maxAttempts)
+	}
+
+	fake.Script(mockportals.Behaviour{})
+	requests := fake.Requests()
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	if fake.Requests() != requests {
+		t.Error("quarantined request reached dofus-portals")
+	}
+
+	replies := broker.Replies()
+	if status := replies[len(replies)-1].Message.Status; status != amqp.RabbitMQMessage_FAILED {
+		t.Errorf("expected quarantined request to fail, got %v", status)
+	}
+}
+
+func TestConsumeResolvesDeadLetterOnSuccess(t *testing.T) {
+	service, fake, broker, deadLetterService := newTestServiceWithDeadLetters(t)
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	fake.Script(mockportals.Behaviour{Malformed: true})
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	if len(deadLetterService.GetDeadLetters()) != 1 {
+		t.Fatal("failed request not recorded")
+	}
+
+	fake.Script(mockportals.Behaviour{})
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+	if deadLetters := deadLetterService.GetDeadLetters(); len(deadLetters) != 0 {
+		t.Errorf("dead letter kept after success: %+v", deadLetters)
+	}
+}
+
+func TestConsumeDoesNotRecordUnavailableSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		behaviour mockportals.Behaviour
+		enabled   bool
+	}{
+		{name: "upstream outage", behaviour: mockportals.Behaviour{StatusCode: http.StatusBadGateway}, enabled: true},
+		{name: "disabled source", enabled: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, fake, broker, deadLetterService := newTestServiceWithDeadLetters(t)
+			service.Reload(configs.Runtime{HTTPTimeout: httpTimeout, DofusPortalsEnabled: test.enabled})
+			service.Consume()
+
+			ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+			fake.Script(test.behaviour)
+			broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+
+			replies := broker.Replies()
+			if len(replies) != 1 || replies[0].Message.Status != amqp.RabbitMQMessage_FAILED {
+				t.Fatalf("expected a failed reply, got %v", replies)
+			}
+			if deadLetters := deadLetterService.GetDeadLetters(); len(deadLetters) != 0 {
+				t.Errorf("request failing for every requester recorded: %+v", deadLetters)
+			}
+		})
+	}
+}
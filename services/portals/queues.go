@@ -0,0 +1,81 @@
+package portals
+
+import (
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/kaellybot/kaelly-portals/utils/replies"
+	"github.com/rs/zerolog/log"
+)
+
+// GetBindings returns the AMQP bindings of the portal request queues, and of the admin
+// queue when admin commands are enabled.
+func GetBindings(config configs.Config) []amqp.Binding {
+	bindings := make([]amqp.Binding, 0, len(config.RequestBindings)+1)
+	for _, binding := range config.RequestBindings {
+		bindings = append(bindings, amqp.Binding{
+			Exchange:   amqp.ExchangeRequest,
+			RoutingKey: binding.RoutingKey,
+			Queue:      binding.Queue,
+		})
+	}
+
+	if config.AdminToken != "" {
+		bindings = append(bindings, amqp.Binding{
+			Exchange:   amqp.ExchangeRequest,
+			RoutingKey: config.AdminRoutingKey,
+			Queue:      config.AdminQueue,
+		})
+	}
+
+	return bindings
+}
+
+func newQueues(bindings []configs.RequestBinding) []*queue {
+	queues := make([]*queue, 0, len(bindings))
+	for _, binding := range bindings {
+		var workers chan struct{}
+		if binding.Workers > 0 {
+			workers = make(chan struct{}, binding.Workers)
+		}
+		queues = append(queues, &queue{binding: binding, workers: workers})
+	}
+
+	return queues
+}
+
+// consumer treats the requests of the queue once a worker is available, provided
+// that a source accepted by the queue knows the portals of their game. Requests
+// are counted as in flight while waiting for a worker, so that they are drained too.
+func (service *Impl) consumer(queue *queue) amqp.MessageConsumer {
+	return func(ctx amqp.Context, message *amqp.RabbitMQMessage) {
+		if !service.requests.acquire() {
+			log.Warn().Err(errStopped).
+				Str(constants.LogCorrelationID, ctx.CorrelationID).
+				Msgf("Request delivered while shutting down, returning failed message")
+			replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
+				message.Language)
+			return
+		}
+		defer service.requests.release()
+
+		game, err := service.route(queue.binding, message)
+		if err != nil {
+			log.Warn().Err(err).
+				Str(constants.LogCorrelationID, ctx.CorrelationID).
+				Str(constants.LogQueue, queue.binding.Queue).
+				Str(constants.LogGame, message.GetGame().String()).
+				Msgf("Cannot route request, returning failed message")
+			replies.FailedAnswer(ctx, service.broker, amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
+				message.Language)
+			return
+		}
+
+		if queue.workers != nil {
+			queue.workers <- struct{}{}
+			defer func() { <-queue.workers }()
+		}
+
+		service.consume(ctx, message, game)
+	}
+}
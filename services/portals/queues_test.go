@@ -0,0 +1,110 @@
+package portals
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mockportals "github.com/kaellybot/kaelly-portals/mocks/dofusportals"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+func TestQueueWorkers(t *testing.T) {
+	service, fake, broker := newTestService(t)
+	service.queues = newQueues([]configs.RequestBinding{{Queue: requestQueueName, Workers: 1}})
+	fake.Script(mockportals.Behaviour{Latency: httpTimeout / 2})
+	service.Consume()
+
+	var wg sync.WaitGroup
+	for _, correlationID := range []string{"first", "second"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := amqp.Context{Context: context.Background(), CorrelationID: correlationID, ReplyTo: "reply"}
+			broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+		}()
+	}
+
+	deadline := time.After(10 * httpTimeout)
+	for len(broker.Replies()) < 2 {
+		// Requests are read before replies, so that a reply sent in between cannot raise the difference.
+		if treated := fake.Requests() - int64(len(broker.Replies())); treated > 1 {
+			t.Fatalf("expected 1 request treated at once, got %d", treated)
+		}
+		select {
+		case <-deadline:
+			t.Fatal("requests not treated in time")
+		case <-time.After(time.Millisecond):
+		}
+	}
+	wg.Wait()
+}
+
+func TestQueueWorkersDrain(t *testing.T) {
+	service, fake, broker := newTestService(t)
+	service.queues = newQueues([]configs.RequestBinding{{Queue: requestQueueName, Workers: 1}})
+	fake.Script(mockportals.Behaviour{Latency: httpTimeout / 2})
+	service.Consume()
+
+	for _, correlationID := range []string{"first", "second"} {
+		go func() {
+			ctx := amqp.Context{Context: context.Background(), CorrelationID: correlationID, ReplyTo: "reply"}
+			broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+		}()
+	}
+
+	deadline := time.After(10 * httpTimeout)
+	for service.requests.size() < 2 {
+		select {
+		case <-deadline:
+			t.Fatal("request waiting for a worker not counted as in flight")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*httpTimeout)
+	defer cancel()
+	if err := service.Drain(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replies := broker.Replies(); len(replies) != 2 {
+		t.Errorf("expected 2 replies once drained, got %d", len(replies))
+	}
+}
+
+func TestQueueSources(t *testing.T) {
+	service, fake, broker := newTestService(t)
+	service.queues = newQueues([]configs.RequestBinding{{Queue: requestQueueName, Sources: []string{"other"}}})
+	service.Consume()
+
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: "correlation", ReplyTo: "reply"}
+	broker.Deliver(requestQueueName, ctx, portalRequest("1", "enu"))
+
+	if fake.Requests() != 0 {
+		t.Error("request reached a source not accepted by its queue")
+	}
+	replies := broker.Replies()
+	if len(replies) != 1 || replies[0].Message.Status != amqp.RabbitMQMessage_FAILED {
+		t.Errorf("expected a failed reply, got %v", replies)
+	}
+}
+
+func TestGetBindings(t *testing.T) {
+	config := configs.Config{
+		RequestBindings: []configs.RequestBinding{{Queue: requestQueueName, RoutingKey: "requests.portals"}},
+		AdminQueue:      "portals-admin",
+		AdminRoutingKey: "admin.portals",
+	}
+	if bindings := GetBindings(config); len(bindings) != 1 {
+		t.Errorf("expected the admin queue not to be bound without admin token, got %v", bindings)
+	}
+
+	config.AdminToken = "secret"
+	bindings := GetBindings(config)
+	if len(bindings) != 2 || bindings[1].Queue != config.AdminQueue ||
+		bindings[1].RoutingKey != config.AdminRoutingKey || bindings[1].Exchange != amqp.ExchangeRequest {
+		t.Errorf("expected the admin queue to be bound next to the request ones, got %v", bindings)
+	}
+}
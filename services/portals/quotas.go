@@ -0,0 +1,87 @@
+package portals
+
+import (
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+)
+
+func newQuotas(limit int, window time.Duration) *quotas {
+	return &quotas{
+		limit:    limit,
+		window:   window,
+		requests: make(map[string][]time.Time),
+	}
+}
+
+// allow records a request for the key and returns false if the key already reached
+// its limit over the sliding window. Empty keys are never limited.
+func (quotas *quotas) allow(key string) bool {
+	if quotas.limit <= 0 || key == "" {
+		return true
+	}
+
+	quotas.mutex.Lock()
+	defer quotas.mutex.Unlock()
+
+	now := time.Now()
+	start := now.Add(-quotas.window)
+	if now.Sub(quotas.cleanedAt) > quotas.window {
+		for otherKey, requests := range quotas.requests {
+			if len(requests) == 0 || requests[len(requests)-1].Before(start) {
+				delete(quotas.requests, otherKey)
+			}
+		}
+		quotas.cleanedAt = now
+	}
+
+	requests := quotas.requests[key]
+	for len(requests) > 0 && requests[0].Before(start) {
+		requests = requests[1:]
+	}
+
+	if len(requests) >= quotas.limit {
+		quotas.requests[key] = requests
+		return false
+	}
+
+	quotas.requests[key] = append(requests, now)
+	return true
+}
+
+func newPositionCache() *positionCache {
+	return &positionCache{
+		positions: make(map[string][]*amqp.PortalPositionAnswer_PortalPosition),
+	}
+}
+
+func (cache *positionCache) get(game amqp.Game, serverID, dimensionID string,
+) ([]*amqp.PortalPositionAnswer_PortalPosition, bool) {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	positions, found := cache.positions[getCacheKey(game, serverID, dimensionID)]
+	return positions, found
+}
+
+func (cache *positionCache) store(game amqp.Game, serverID, dimensionID string,
+	positions []*amqp.PortalPositionAnswer_PortalPosition) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.positions[getCacheKey(game, serverID, dimensionID)] = positions
+}
+
+func (cache *positionCache) flush() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.positions = make(map[string][]*amqp.PortalPositionAnswer_PortalPosition)
+}
+
+func (cache *positionCache) size() int {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+	return len(cache.positions)
+}
+
+func getCacheKey(game amqp.Game, serverID, dimensionID string) string {
+	return game.String() + "/" + serverID + "/" + dimensionID
+}
@@ -0,0 +1,112 @@
+package portals
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/brokers"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+)
+
+func TestQuotas(t *testing.T) {
+	quotas := newQuotas(2, 10*time.Millisecond)
+	if !quotas.allow("user") || !quotas.allow("user") {
+		t.Fatal("requests under the limit rejected")
+	}
+	if quotas.allow("user") {
+		t.Error("request over the limit allowed")
+	}
+	if !quotas.allow("other") {
+		t.Error("quota shared between users")
+	}
+	if !quotas.allow("") {
+		t.Error("request without user limited")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !quotas.allow("user") {
+		t.Error("request rejected once the window slid")
+	}
+}
+
+func TestQuotasDisabled(t *testing.T) {
+	quotas := newQuotas(0, time.Minute)
+	for range 10 {
+		if !quotas.allow("user") {
+			t.Fatal("request limited while quotas are disabled")
+		}
+	}
+}
+
+func TestConsumeOverQuota(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           string
+		cached         bool
+		expectedStatus amqp.RabbitMQMessage_Status
+	}{
+		{name: "cache mode with cached positions", mode: constants.QuotaModeCache, cached: true,
+			expectedStatus: amqp.RabbitMQMessage_SUCCESS},
+		{name: "cache mode without cached positions", mode: constants.QuotaModeCache,
+			expectedStatus: amqp.RabbitMQMessage_FAILED},
+		{name: "reject mode", mode: constants.QuotaModeReject, cached: true,
+			expectedStatus: amqp.RabbitMQMessage_FAILED},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, fake, broker := newTestService(t)
+			service.userQuotas = newQuotas(1, time.Minute)
+			service.quotaMode = test.mode
+			service.Consume()
+
+			if test.cached {
+				cachedRequest := portalRequest("1", "enu")
+				cachedRequest.UserID = "other"
+				deliver(t, broker, "cached", cachedRequest)
+			}
+
+			first := portalRequest("1", "sram")
+			first.UserID = "user"
+			deliver(t, broker, "first", first)
+
+			requests := fake.Requests()
+			second := portalRequest("1", "enu")
+			second.UserID = "user"
+			deliver(t, broker, "second", second)
+
+			if fake.Requests() != requests {
+				t.Error("request over quota reached dofus-portals")
+			}
+
+			replies := broker.Replies()
+			reply := replies[len(replies)-1]
+			if reply.Message.Status != test.expectedStatus {
+				t.Errorf("expected status %v, got %v", test.expectedStatus, reply.Message.Status)
+			}
+		})
+	}
+}
+
+func TestPositionCacheGames(t *testing.T) {
+	cache := newPositionCache()
+	positions := []*amqp.PortalPositionAnswer_PortalPosition{{ServerId: "1", DimensionId: "enu"}}
+	cache.store(amqp.Game_DOFUS_GAME, "1", "enu", positions)
+
+	if _, found := cache.get(amqp.Game_DOFUS_GAME, "1", "enu"); !found {
+		t.Error("positions not cached")
+	}
+	if _, found := cache.get(amqp.Game_DOFUS_TOUCH, "1", "enu"); found {
+		t.Error("positions cached for another game answered")
+	}
+}
+
+func deliver(t *testing.T, broker *brokers.Broker, correlationID string, message *amqp.RabbitMQMessage) {
+	t.Helper()
+	ctx := amqp.Context{Context: context.Background(), CorrelationID: correlationID, ReplyTo: "reply"}
+	if !broker.Deliver(requestQueueName, ctx, message) {
+		t.Fatalf("no consumer registered on %s", requestQueueName)
+	}
+}
@@ -1,44 +1,170 @@
 package portals
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	amqp "github.com/kaellybot/kaelly-amqp"
 	"github.com/kaellybot/kaelly-portals/payloads/dofusportals"
 	"github.com/kaellybot/kaelly-portals/services/areas"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/confidences"
+	"github.com/kaellybot/kaelly-portals/services/deadletters"
 	"github.com/kaellybot/kaelly-portals/services/dimensions"
 	"github.com/kaellybot/kaelly-portals/services/servers"
+	"github.com/kaellybot/kaelly-portals/services/snapshots"
 	"github.com/kaellybot/kaelly-portals/services/subareas"
 	"github.com/kaellybot/kaelly-portals/services/transports"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/kaellybot/kaelly-portals/utils/recorders"
 )
 
 const (
-	requestQueueName   = "portals-requests"
-	requestsRoutingkey = "requests.portals"
-	answersRoutingkey  = "answers.portals"
+	answersRoutingkey = "answers.portals"
 
-	httpHeader   = "header"
 	httpAPIToken = "token"
+
+	primaryToken         = 0
+	tokenRefreshInterval = 30 * time.Second
+
+	statusError              = "error"
+	endpointFailureThreshold = 3
+	endpointCooldown         = 30 * time.Second
 )
 
+//nolint:gochecknoglobals // Read-only lookup, indexed by token position.
+var tokenNames = []string{"primary", "secondary"}
+
 var (
 	errInvalidMessage = errors.New("invalid request portal, type is not the good one" +
 		" and/or the dedicated message is not filled")
-	errStatusNotOK = errors.New("status Code is not OK")
+	errStatusNotOK  = errors.New("status Code is not OK")
+	errNoEndpoint   = errors.New("no Dofus Portals endpoint configured")
+	errUnavailable  = errors.New("every Dofus Portals endpoint failed")
+	errDisabled     = errors.New("dofus Portals source is disabled")
+	errQuarantined  = errors.New("request is quarantined")
+	errRateLimited  = errors.New("rate limited")
+	errStopped      = errors.New("requests are no longer consumed")
+	errNoSource     = errors.New("no source accepted by the queue knows the portals of this game")
+	errGameMismatch = errors.New("reference does not belong to the requested game")
 )
 
 type Service interface {
 	Consume()
+	GetPortals(ctx context.Context, serverID, dimensionID string) ([]*amqp.PortalPositionAnswer_PortalPosition, error)
+	GetDofusPortalsServers(ctx context.Context) ([]dofusportals.Server, error)
+	GetDofusPortalsDimensions(ctx context.Context) ([]dofusportals.Dimension, error)
+	GetDofusPortalsPortals(ctx context.Context, dofusPortalsServerID string) ([]dofusportals.Portal, error)
+	Reload(runtime configs.Runtime)
+	Stop()
+	Drain(ctx context.Context) error
+	FlushCache()
+	SetEnabled(enabled bool)
+	GetStats() Stats
+}
+
+// Stats describes the internal state of the service; CachedPositions counts
+// the server and dimension pairs having last known positions.
+type Stats struct {
+	SourceEnabled    bool `json:"sourceEnabled"`
+	InFlightRequests int  `json:"inFlightRequests"`
+	CachedPositions  int  `json:"cachedPositions"`
+	Endpoints        int  `json:"endpoints"`
+	HealthyEndpoints int  `json:"healthyEndpoints"`
 }
 
 type Impl struct {
-	dofusPortalsClient dofusportals.ClientInterface
-	broker             amqp.MessageBroker
-	httpTimeout        time.Duration
-	serverService      servers.Service
-	dimensionService   dimensions.Service
-	areaService        areas.Service
-	subAreaService     subareas.Service
-	transportService   transports.Service
+	endpoints         []*endpoint
+	broker            amqp.MessageBroker
+	httpTimeout       atomic.Int64
+	enabled           atomic.Bool
+	configuredEnabled atomic.Pointer[bool]
+	serverService     servers.Service
+	dimensionService  dimensions.Service
+	areaService       areas.Service
+	subAreaService    subareas.Service
+	transportService  transports.Service
+	deadLetterService deadletters.Service
+	confidenceService confidences.Service
+	snapshotService   snapshots.Service
+	boundsService     bounds.Service
+	deduplicator      *deduplicator
+	userQuotas        *quotas
+	quotaMode         string
+	suspiciousMode    string
+	cache             *positionCache
+	requests          *inFlight
+	queues            []*queue
+}
+
+type endpoint struct {
+	url            string
+	client         dofusportals.ClientInterface
+	mutex          sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// credentials authenticates dofus-portals requests, with a primary and a secondary
+// token to allow rotations without downtime.
+type credentials struct {
+	doer   recorders.HTTPRequestDoer
+	mutex  sync.Mutex
+	tokens []*tokenSource
+	active int
+}
+
+type tokenSource struct {
+	value  string
+	file   string
+	readAt time.Time
+}
+
+// deduplicator keeps the replies of recently answered correlation IDs,
+// so that redelivered requests get the same answer without reaching dofus-portals.
+type deduplicator struct {
+	ttl     atomic.Int64
+	mutex   sync.Mutex
+	entries map[string]*deduplicationEntry
+}
+
+type deduplicationEntry struct {
+	correlationID string
+	done          chan struct{}
+	reply         *amqp.RabbitMQMessage
+	expiresAt     time.Time
+}
+
+// quotas limits the number of requests per key over a sliding window.
+type quotas struct {
+	limit     int
+	window    time.Duration
+	mutex     sync.Mutex
+	requests  map[string][]time.Time
+	cleanedAt time.Time
+}
+
+// positionCache keeps the last positions successfully answered per game, server and dimension,
+// to answer requests that cannot reach dofus-portals.
+type positionCache struct {
+	mutex     sync.RWMutex
+	positions map[string][]*amqp.PortalPositionAnswer_PortalPosition
+}
+
+// queue is a queue of portal requests; workers holds a token per request being
+// treated, nil meaning no limit.
+type queue struct {
+	binding configs.RequestBinding
+	workers chan struct{}
+}
+
+// inFlight counts the requests being treated, to wait for them on shutdown.
+type inFlight struct {
+	mutex   sync.Mutex
+	count   int
+	stopped bool
+	idle    chan struct{}
 }
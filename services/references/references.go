@@ -0,0 +1,119 @@
+package references
+
+import (
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/areas"
+	"github.com/kaellybot/kaelly-portals/repositories/bounds"
+	"github.com/kaellybot/kaelly-portals/repositories/dimensions"
+	"github.com/kaellybot/kaelly-portals/repositories/labels"
+	"github.com/kaellybot/kaelly-portals/repositories/references"
+	"github.com/kaellybot/kaelly-portals/repositories/servers"
+	"github.com/kaellybot/kaelly-portals/repositories/subareas"
+	"github.com/kaellybot/kaelly-portals/repositories/transports"
+)
+
+func New(serverRepo servers.Repository, dimensionRepo dimensions.Repository,
+	areaRepo areas.Repository, subAreaRepo subareas.Repository,
+	transportRepo transports.Repository, boundsRepo bounds.Repository,
+	labelRepo labels.Repository) *Impl {
+	return &Impl{
+		serverRepo:    serverRepo,
+		dimensionRepo: dimensionRepo,
+		areaRepo:      areaRepo,
+		subAreaRepo:   subAreaRepo,
+		transportRepo: transportRepo,
+		boundsRepo:    boundsRepo,
+		labelRepo:     labelRepo,
+	}
+}
+
+// Export writes every reference data held by the repositories to a snapshot file,
+// as YAML if its extension is .yaml or .yml, as JSON otherwise.
+func (service *Impl) Export(path string) (entities.ReferenceSnapshot, error) {
+	snapshot, err := service.getSnapshot()
+	if err != nil {
+		return entities.ReferenceSnapshot{}, err
+	}
+
+	if err = references.New(path).Save(snapshot); err != nil {
+		return entities.ReferenceSnapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// Import saves every reference data of a snapshot file into the repositories,
+// existing entries being overwritten; entries missing from the file are kept.
+func (service *Impl) Import(path string) (entities.ReferenceSnapshot, error) {
+	fileRepo := references.New(path)
+	if err := fileRepo.Load(); err != nil {
+		return entities.ReferenceSnapshot{}, err
+	}
+
+	snapshot := fileRepo.GetSnapshot()
+	for _, server := range snapshot.Servers {
+		if err := service.serverRepo.SaveServer(server); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+	for _, dimension := range snapshot.Dimensions {
+		if err := service.dimensionRepo.SaveDimension(dimension); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+	for _, area := range snapshot.Areas {
+		if err := service.areaRepo.SaveArea(area); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+	for _, subArea := range snapshot.SubAreas {
+		if err := service.subAreaRepo.SaveSubArea(subArea); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+	for _, transportType := range snapshot.TransportTypes {
+		if err := service.transportRepo.SaveTransportType(transportType); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+	for _, mapBounds := range snapshot.MapBounds {
+		if err := service.boundsRepo.SaveMapBounds(mapBounds); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+	for _, label := range snapshot.Labels {
+		if err := service.labelRepo.SaveLabel(label); err != nil {
+			return entities.ReferenceSnapshot{}, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+func (service *Impl) getSnapshot() (entities.ReferenceSnapshot, error) {
+	var snapshot entities.ReferenceSnapshot
+	var err error
+	snapshot.CreatedAt = time.Now().UTC()
+	if snapshot.Servers, err = service.serverRepo.GetServers(); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Dimensions, err = service.dimensionRepo.GetDimensions(); err != nil {
+		return snapshot, err
+	}
+	if snapshot.Areas, err = service.areaRepo.GetAreas(); err != nil {
+		return snapshot, err
+	}
+	if snapshot.SubAreas, err = service.subAreaRepo.GetSubAreas(); err != nil {
+		return snapshot, err
+	}
+	if snapshot.TransportTypes, err = service.transportRepo.GetTransportTypes(); err != nil {
+		return snapshot, err
+	}
+	if snapshot.MapBounds, err = service.boundsRepo.GetMapBounds(); err != nil {
+		return snapshot, err
+	}
+	snapshot.Labels, err = service.labelRepo.GetLabels()
+	return snapshot, err
+}
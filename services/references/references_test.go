@@ -0,0 +1,96 @@
+package references
+
+import (
+	"path/filepath"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+// database records every reference data saved.
+type database struct {
+	references.Servers
+	references.Dimensions
+	references.Areas
+	references.SubAreas
+	references.TransportTypes
+	references.MapBounds
+	references.Labels
+	saved []any
+}
+
+func (db *database) SaveServer(server entities.Server) error {
+	db.saved = append(db.saved, server)
+	return nil
+}
+
+func (db *database) SaveDimension(dimension entities.Dimension) error {
+	db.saved = append(db.saved, dimension)
+	return nil
+}
+
+func (db *database) SaveArea(area entities.Area) error {
+	db.saved = append(db.saved, area)
+	return nil
+}
+
+func (db *database) SaveSubArea(subArea entities.SubArea) error {
+	db.saved = append(db.saved, subArea)
+	return nil
+}
+
+func (db *database) SaveTransportType(transportType entities.TransportType) error {
+	db.saved = append(db.saved, transportType)
+	return nil
+}
+
+func (db *database) SaveMapBounds(mapBounds entities.MapBounds) error {
+	db.saved = append(db.saved, mapBounds)
+	return nil
+}
+
+func (db *database) SaveLabel(label entities.Label) error {
+	db.saved = append(db.saved, label)
+	return nil
+}
+
+func TestExportImport(t *testing.T) {
+	source := New(
+		references.Servers{{ID: "1", DofusPortalsID: "agride"}},
+		references.Dimensions{{ID: "enu", DofusPortalsID: "enutrosor"}, {ID: "sram", DofusPortalsID: "srambad"}},
+		references.Areas{{ID: "area-astrub", DofusPortalsID: "astrub"}},
+		references.SubAreas{{ID: "subarea-astrub", DofusPortalsID: "cite_astrub"}},
+		references.TransportTypes{{ID: "transport-zaap", DofusPortalsID: "zaap"}},
+		references.MapBounds{{ID: 1, AreaID: "area-astrub", MinX: 0, MaxX: 10, MinY: -20, MaxY: -10}},
+		references.Labels{{ReferenceType: "dimension", ReferenceID: "enu", Language: amqp.Language_FR,
+			Label: "Enutrosor"}},
+	)
+
+	for _, file := range []string{"references.json", "references.yaml"} {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), file)
+			if _, err := source.Export(path); err != nil {
+				t.Fatalf("cannot export: %v", err)
+			}
+
+			target := &database{}
+			service := New(target, target, target, target, target, target, target)
+			snapshot, err := service.Import(path)
+			if err != nil {
+				t.Fatalf("cannot import: %v", err)
+			}
+
+			if len(target.saved) != 8 {
+				t.Errorf("expected 8 saved entries, got %d: %+v", len(target.saved), target.saved)
+			}
+			if len(snapshot.Labels) != 1 || snapshot.Labels[0].Language != amqp.Language_FR {
+				t.Errorf("labels not imported as exported: %+v", snapshot.Labels)
+			}
+			if len(snapshot.MapBounds) != 1 || snapshot.MapBounds[0].MinY != -20 {
+				t.Errorf("map bounds not imported as exported: %+v", snapshot.MapBounds)
+			}
+		})
+	}
+}
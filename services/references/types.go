@@ -0,0 +1,27 @@
+package references
+
+import (
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/areas"
+	"github.com/kaellybot/kaelly-portals/repositories/bounds"
+	"github.com/kaellybot/kaelly-portals/repositories/dimensions"
+	"github.com/kaellybot/kaelly-portals/repositories/labels"
+	"github.com/kaellybot/kaelly-portals/repositories/servers"
+	"github.com/kaellybot/kaelly-portals/repositories/subareas"
+	"github.com/kaellybot/kaelly-portals/repositories/transports"
+)
+
+type Service interface {
+	Export(path string) (entities.ReferenceSnapshot, error)
+	Import(path string) (entities.ReferenceSnapshot, error)
+}
+
+type Impl struct {
+	serverRepo    servers.Repository
+	dimensionRepo dimensions.Repository
+	areaRepo      areas.Repository
+	subAreaRepo   subareas.Repository
+	transportRepo transports.Repository
+	boundsRepo    bounds.Repository
+	labelRepo     labels.Repository
+}
@@ -0,0 +1,73 @@
+package reports
+
+import (
+	"fmt"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+)
+
+func New(serverService servers.Service, dimensionService dimensions.Service,
+	boundsService bounds.Service) *Impl {
+	return &Impl{
+		serverService:    serverService,
+		dimensionService: dimensionService,
+		boundsService:    boundsService,
+	}
+}
+
+// Report validates a portal report. No source accepts writes, so a valid report is
+// rejected with errNoWritableSource anyway.
+func (service *Impl) Report(report Report) error {
+	if report.X < minCoordinate || report.X > maxCoordinate ||
+		report.Y < minCoordinate || report.Y > maxCoordinate {
+		return fmt.Errorf("%w: [%d,%d]", errInvalidCoordinates, report.X, report.Y)
+	}
+
+	if err := service.boundsService.ValidateCoordinates(report.X, report.Y); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidCoordinates, err)
+	}
+
+	server, found := service.serverService.GetServer(report.ServerID)
+	if !found {
+		return fmt.Errorf("%w: %s", errUnknownServer, report.ServerID)
+	}
+
+	dimension, found := service.dimensionService.GetDimension(report.DimensionID)
+	if !found {
+		return fmt.Errorf("%w: %s", errUnknownDimension, report.DimensionID)
+	}
+
+	if err := validateGame(report.Game, server, dimension); err != nil {
+		return err
+	}
+
+	return errNoWritableSource
+}
+
+// validateGame checks, as portal requests are, that the server and the dimension belong
+// to the game and that a source knows its portals.
+func validateGame(game amqp.Game, server entities.Server, dimension entities.Dimension) error {
+	if !constants.MatchesGame(server.Game, game) {
+		return fmt.Errorf("%w: server %v belongs to %v", errGameMismatch, server.ID, server.Game)
+	}
+	if game == amqp.Game_ANY_GAME {
+		game = server.Game
+	}
+
+	if !constants.MatchesGame(dimension.Game, game) {
+		return fmt.Errorf("%w: dimension %v belongs to %v", errGameMismatch, dimension.ID, dimension.Game)
+	}
+
+	for _, source := range constants.GetSources() {
+		if source.Supports(game) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %v", errUnsupportedGame, game)
+}
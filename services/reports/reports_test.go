@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"errors"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/references"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+)
+
+func TestReport(t *testing.T) {
+	refs := references.New(
+		references.Servers{
+			{ID: "1", DofusPortalsID: "agride"},
+			{ID: "touch", DofusPortalsID: "touch", Game: amqp.Game_DOFUS_TOUCH},
+		},
+		references.Dimensions{
+			{ID: "enu", DofusPortalsID: "enutrosor"},
+			{ID: "retro", DofusPortalsID: "retro", Game: amqp.Game_DOFUS_RETRO},
+		},
+		references.Areas{}, references.SubAreas{}, references.TransportTypes{},
+	)
+	boundsService, err := bounds.New(references.MapBounds{
+		entities.MapBounds{AreaID: "astrub", MinX: -30, MaxX: 0, MinY: 0, MaxY: 30},
+	})
+	if err != nil {
+		t.Fatalf("cannot build bounds service: %v", err)
+	}
+	service := New(refs.Servers, refs.Dimensions, boundsService)
+
+	tests := []struct {
+		name          string
+		report        Report
+		expectedError error
+	}{
+		{
+			name:          "coordinates out of bounds",
+			report:        Report{ServerID: "1", DimensionID: "enu", X: 1, Y: maxCoordinate + 1},
+			expectedError: errInvalidCoordinates,
+		},
+		{
+			name:          "coordinates out of known map bounds",
+			report:        Report{ServerID: "1", DimensionID: "enu", X: 10, Y: 12},
+			expectedError: errInvalidCoordinates,
+		},
+		{
+			name:          "unknown server",
+			report:        Report{ServerID: "2", DimensionID: "enu"},
+			expectedError: errUnknownServer,
+		},
+		{
+			name:          "unknown dimension",
+			report:        Report{ServerID: "1", DimensionID: "sram"},
+			expectedError: errUnknownDimension,
+		},
+		{
+			name:          "server of another game",
+			report:        Report{ServerID: "touch", DimensionID: "enu", X: -25, Y: 12, Game: amqp.Game_DOFUS_GAME},
+			expectedError: errGameMismatch,
+		},
+		{
+			name:          "dimension of another game",
+			report:        Report{ServerID: "1", DimensionID: "retro", X: -25, Y: 12, Game: amqp.Game_DOFUS_GAME},
+			expectedError: errGameMismatch,
+		},
+		{
+			name:          "game without source",
+			report:        Report{ServerID: "touch", DimensionID: "enu", X: -25, Y: 12},
+			expectedError: errUnsupportedGame,
+		},
+		{
+			name:          "read-only sources",
+			report:        Report{ServerID: "1", DimensionID: "enu", X: -25, Y: 12, IsInCanopy: true},
+			expectedError: errNoWritableSource,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := service.Report(test.report); !errors.Is(err, test.expectedError) {
+				t.Errorf("expected %v, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package reports
+
+import (
+	"errors"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+
+	"github.com/kaellybot/kaelly-portals/services/bounds"
+	"github.com/kaellybot/kaelly-portals/services/dimensions"
+	"github.com/kaellybot/kaelly-portals/services/servers"
+)
+
+const (
+	// Coordinates beyond these bounds are rejected without reaching any source.
+	minCoordinate = -150
+	maxCoordinate = 150
+)
+
+var (
+	errInvalidCoordinates = errors.New("coordinates out of the world map bounds")
+	errUnknownServer      = errors.New("unknown server")
+	errUnknownDimension   = errors.New("unknown dimension")
+	errGameMismatch       = errors.New("reference does not belong to the reported game")
+	errUnsupportedGame    = errors.New("no source knows the portals of this game")
+	errNoWritableSource   = errors.New("no source accepts portal reports")
+)
+
+type Service interface {
+	Report(report Report) error
+}
+
+// Report of a portal position by a community member, based on internal IDs.
+type Report struct {
+	ServerID    string
+	DimensionID string
+	X           int64
+	Y           int64
+	IsInCanopy  bool
+	UserID      string
+	// Game of the position, the one of its server when ANY_GAME.
+	Game amqp.Game
+}
+
+type Impl struct {
+	serverService    servers.Service
+	dimensionService dimensions.Service
+	boundsService    bounds.Service
+}
@@ -6,11 +6,24 @@ import (
 )
 
 func New(serverRepo servers.Repository) (*Impl, error) {
-	serverEntities, err := serverRepo.GetServers()
-	if err != nil {
+	service := Impl{
+		serverRepo: serverRepo,
+	}
+
+	if err := service.Reload(); err != nil {
 		return nil, err
 	}
 
+	return &service, nil
+}
+
+// Reload loads servers again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	serverEntities, err := service.serverRepo.GetServers()
+	if err != nil {
+		return err
+	}
+
 	servers := make(map[string]entities.Server)
 	dofusPortalsServers := make(map[string]entities.Server)
 	for _, server := range serverEntities {
@@ -18,19 +31,23 @@ func New(serverRepo servers.Repository) (*Impl, error) {
 		dofusPortalsServers[server.DofusPortalsID] = server
 	}
 
-	return &Impl{
-		servers:             servers,
-		dofusPortalsServers: dofusPortalsServers,
-		serverRepo:          serverRepo,
-	}, nil
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.servers = servers
+	service.dofusPortalsServers = dofusPortalsServers
+	return nil
 }
 
 func (service *Impl) GetServer(id string) (entities.Server, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	server, found := service.servers[id]
 	return server, found
 }
 
 func (service *Impl) FindServerByDofusPortalsID(dofusPortalsID string) (entities.Server, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	server, found := service.dofusPortalsServers[dofusPortalsID]
 	return server, found
 }
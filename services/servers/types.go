@@ -1,6 +1,8 @@
 package servers
 
 import (
+	"sync"
+
 	"github.com/kaellybot/kaelly-portals/models/entities"
 	"github.com/kaellybot/kaelly-portals/repositories/servers"
 )
@@ -8,9 +10,11 @@ import (
 type Service interface {
 	GetServer(id string) (entities.Server, bool)
 	FindServerByDofusPortalsID(dofusPortalsID string) (entities.Server, bool)
+	Reload() error
 }
 
 type Impl struct {
+	mutex               sync.RWMutex
 	servers             map[string]entities.Server
 	dofusPortalsServers map[string]entities.Server
 	serverRepo          servers.Repository
@@ -0,0 +1,110 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/snapshots"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/rs/zerolog/log"
+)
+
+func New(snapshotRepo snapshots.Repository) *Impl {
+	return &Impl{
+		lastKeys:     make(map[string]string),
+		now:          time.Now,
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// Record saves the positions reported since the previous call, except undated ones.
+// Failures are logged only, since snapshots must not fail portal requests.
+func (service *Impl) Record(ctx context.Context, positions []*amqp.PortalPositionAnswer_PortalPosition) {
+	_, span := insights.StartSpan(ctx, "snapshots.record")
+	defer span.End()
+
+	for _, position := range positions {
+		if position.GetPosition() == nil {
+			continue
+		}
+
+		snapshot, dated := mapSnapshot(position)
+		if !dated {
+			log.Debug().
+				Str(constants.LogServerID, snapshot.ServerID).
+				Str(constants.LogDimensionID, snapshot.DimensionID).
+				Msgf("Portal position neither created nor updated at a known date, snapshot ignored")
+			continue
+		}
+
+		dimensionKey := fmt.Sprintf("%s/%s", snapshot.ServerID, snapshot.DimensionID)
+		service.mutex.Lock()
+		unchanged := service.lastKeys[dimensionKey] == snapshot.Key
+		service.lastKeys[dimensionKey] = snapshot.Key
+		service.mutex.Unlock()
+		if unchanged {
+			continue
+		}
+
+		if err := service.snapshotRepo.SaveSnapshot(&snapshot); err != nil {
+			log.Error().Err(err).
+				Str(constants.LogServerID, snapshot.ServerID).
+				Str(constants.LogDimensionID, snapshot.DimensionID).
+				Msgf("Cannot save portal snapshot, snapshot ignored")
+			service.mutex.Lock()
+			delete(service.lastKeys, dimensionKey)
+			service.mutex.Unlock()
+		}
+	}
+}
+
+// GetLeaderboard returns the best contributors of a server over a period;
+// an empty serverID means every server.
+func (service *Impl) GetLeaderboard(serverID string, period constants.Period,
+) ([]entities.Contribution, error) {
+	since, err := constants.GetPeriodStart(period, service.now())
+	if err != nil {
+		return nil, err
+	}
+
+	return service.snapshotRepo.GetContributions(serverID, since, leaderboardSize)
+}
+
+// mapSnapshot returns false if the position has neither update nor creation date.
+func mapSnapshot(position *amqp.PortalPositionAnswer_PortalPosition) (entities.Snapshot, bool) {
+	reporter := position.GetUpdatedBy()
+	reportedAt := position.GetUpdatedAt()
+	if reporter == "" {
+		reporter = position.GetCreatedBy()
+	}
+	if reportedAt == nil {
+		reportedAt = position.GetCreatedAt()
+	}
+
+	snapshot := entities.Snapshot{
+		ServerID:        position.GetServerId(),
+		DimensionID:     position.GetDimensionId(),
+		X:               position.GetPosition().GetX(),
+		Y:               position.GetPosition().GetY(),
+		IsInCanopy:      position.GetPosition().GetIsInCanopy(),
+		AreaID:          position.GetPosition().GetTransport().GetAreaId(),
+		SubAreaID:       position.GetPosition().GetTransport().GetSubAreaId(),
+		TransportTypeID: position.GetPosition().GetTransport().GetTypeId(),
+		RemainingUses:   position.GetRemainingUses(),
+		Reporter:        reporter,
+		Source:          position.GetSource().GetName(),
+	}
+	if reportedAt == nil {
+		return snapshot, false
+	}
+
+	snapshot.ReportedAt = reportedAt.AsTime()
+	snapshot.Key = fmt.Sprintf("%s/%s/%d/%d/%s/%d", snapshot.ServerID, snapshot.DimensionID,
+		snapshot.X, snapshot.Y, snapshot.Reporter, snapshot.ReportedAt.Unix())
+
+	return snapshot, true
+}
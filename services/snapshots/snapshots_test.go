@@ -0,0 +1,102 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	mocksnapshots "github.com/kaellybot/kaelly-portals/mocks/snapshots"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//nolint:gochecknoglobals // Fixed clock shared by tests.
+var now = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func newPosition(dimensionID, createdBy, updatedBy string, reportedAt time.Time,
+) *amqp.PortalPositionAnswer_PortalPosition {
+	position := &amqp.PortalPositionAnswer_PortalPosition{
+		ServerId:    "imagiro",
+		DimensionId: dimensionID,
+		CreatedBy:   createdBy,
+		CreatedAt:   timestamppb.New(reportedAt),
+		Position:    &amqp.PortalPositionAnswer_PortalPosition_Position{X: 1, Y: 2},
+	}
+	if updatedBy != "" {
+		position.UpdatedBy = updatedBy
+		position.UpdatedAt = timestamppb.New(reportedAt)
+	}
+	return position
+}
+
+func TestRecord(t *testing.T) {
+	repo := mocksnapshots.New()
+	service := New(repo)
+
+	created := newPosition("enutrosor", "creator", "", now.Add(-time.Hour))
+	updated := newPosition("enutrosor", "creator", "updater", now)
+	unknown := &amqp.PortalPositionAnswer_PortalPosition{ServerId: "imagiro", DimensionId: "srambad"}
+	undated := &amqp.PortalPositionAnswer_PortalPosition{ServerId: "imagiro", DimensionId: "xelorium",
+		Position: &amqp.PortalPositionAnswer_PortalPosition_Position{X: 1, Y: 2}}
+
+	service.Record(context.Background(), []*amqp.PortalPositionAnswer_PortalPosition{created, unknown, undated})
+	service.Record(context.Background(), []*amqp.PortalPositionAnswer_PortalPosition{created})
+	service.Record(context.Background(), []*amqp.PortalPositionAnswer_PortalPosition{updated})
+
+	snapshots := repo.GetSnapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snapshots))
+	}
+	if snapshots[0].Reporter != "creator" || snapshots[1].Reporter != "updater" {
+		t.Errorf("unexpected reporters: %q, %q", snapshots[0].Reporter, snapshots[1].Reporter)
+	}
+
+	// Restarted service: already saved snapshots are not duplicated.
+	New(repo).Record(context.Background(), []*amqp.PortalPositionAnswer_PortalPosition{updated})
+	if count := len(repo.GetSnapshots()); count != 2 {
+		t.Errorf("expected 2 snapshots after restart, got %d", count)
+	}
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	service := New(mocksnapshots.New(
+		entities.Snapshot{Key: "1", ServerID: "imagiro", Reporter: "frequent", ReportedAt: now.Add(-time.Hour)},
+		entities.Snapshot{Key: "2", ServerID: "imagiro", Reporter: "frequent", ReportedAt: now.Add(-48 * time.Hour)},
+		entities.Snapshot{Key: "3", ServerID: "imagiro", Reporter: "frequent", ReportedAt: now.Add(-72 * time.Hour)},
+		entities.Snapshot{Key: "4", ServerID: "imagiro", Reporter: "recent", ReportedAt: now.Add(-time.Minute)},
+		entities.Snapshot{Key: "5", ServerID: "imagiro", Reporter: "recent", ReportedAt: now.Add(-2 * time.Minute)},
+		entities.Snapshot{Key: "6", ServerID: "draconiros", Reporter: "other", ReportedAt: now},
+	))
+	service.now = func() time.Time { return now }
+
+	contributions, err := service.GetLeaderboard("imagiro", constants.PeriodDay)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contributions) != 2 || contributions[0].Reporter != "recent" || contributions[0].Contributions != 2 {
+		t.Errorf("unexpected daily leaderboard: %+v", contributions)
+	}
+
+	contributions, err = service.GetLeaderboard("imagiro", constants.PeriodWeek)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contributions) != 2 || contributions[0].Reporter != "frequent" || contributions[0].Contributions != 3 {
+		t.Errorf("unexpected weekly leaderboard: %+v", contributions)
+	}
+
+	contributions, err = service.GetLeaderboard("", constants.PeriodAll)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(contributions) != 3 {
+		t.Errorf("expected contributors of every server, got %+v", contributions)
+	}
+
+	if _, err = service.GetLeaderboard("imagiro", "year"); !errors.Is(err, constants.ErrUnknownPeriod) {
+		t.Errorf("expected %v, got %v", constants.ErrUnknownPeriod, err)
+	}
+}
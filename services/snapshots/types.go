@@ -0,0 +1,30 @@
+package snapshots
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/snapshots"
+)
+
+const (
+	leaderboardSize = 10
+)
+
+type Service interface {
+	Record(ctx context.Context, positions []*amqp.PortalPositionAnswer_PortalPosition)
+	GetLeaderboard(serverID string, period constants.Period) ([]entities.Contribution, error)
+}
+
+// Impl persists a snapshot for each report seen, remembering the last one
+// per server and dimension so that unchanged positions are not saved again.
+type Impl struct {
+	mutex        sync.Mutex
+	lastKeys     map[string]string
+	now          func() time.Time
+	snapshotRepo snapshots.Repository
+}
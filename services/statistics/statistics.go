@@ -0,0 +1,28 @@
+package statistics
+
+import (
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/snapshots"
+)
+
+func New(snapshotRepo snapshots.Repository) *Impl {
+	return &Impl{
+		now:          time.Now,
+		snapshotRepo: snapshotRepo,
+	}
+}
+
+// GetStatistics returns the statistics of a server over a period, for each dimension
+// having snapshots or only one; an empty dimensionID means every dimension.
+func (service *Impl) GetStatistics(serverID, dimensionID string, period constants.Period,
+) ([]entities.Statistics, error) {
+	since, err := constants.GetPeriodStart(period, service.now())
+	if err != nil {
+		return nil, err
+	}
+
+	return service.snapshotRepo.GetStatistics(serverID, dimensionID, since, frequenciesSize)
+}
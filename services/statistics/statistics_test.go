@@ -0,0 +1,77 @@
+package statistics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	mocksnapshots "github.com/kaellybot/kaelly-portals/mocks/snapshots"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+//nolint:gochecknoglobals // Fixed clock shared by tests.
+var now = time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+func newSnapshot(dimensionID string, x, y int64, areaID string, canopy bool, remainingUses int64,
+	reportedAt time.Time) entities.Snapshot {
+	return entities.Snapshot{
+		Key:           fmt.Sprintf("%s/%d/%d/%d", dimensionID, x, y, reportedAt.Unix()),
+		ServerID:      "imagiro",
+		DimensionID:   dimensionID,
+		X:             x,
+		Y:             y,
+		IsInCanopy:    canopy,
+		AreaID:        areaID,
+		SubAreaID:     "sub-" + areaID,
+		RemainingUses: remainingUses,
+		ReportedAt:    reportedAt,
+	}
+}
+
+func TestGetStatistics(t *testing.T) {
+	service := New(mocksnapshots.New(
+		newSnapshot("enutrosor", 1, 2, "astrub", false, 100, now.Add(-48*time.Hour)),
+		newSnapshot("enutrosor", 1, 2, "astrub", false, 60, now.Add(-40*time.Hour)),
+		newSnapshot("enutrosor", 5, 5, "amakna", true, 100, now.Add(-24*time.Hour)),
+		newSnapshot("enutrosor", 1, 2, "astrub", false, 100, now),
+		newSnapshot("srambad", 3, 3, "amakna", false, 50, now.Add(-time.Hour)),
+		newSnapshot("srambad", 3, 3, "amakna", false, 40, now.Add(-40*24*time.Hour)),
+	))
+	service.now = func() time.Time { return now }
+
+	statistics, err := service.GetStatistics("imagiro", "", constants.PeriodWeek)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statistics) != 2 {
+		t.Fatalf("expected statistics of 2 dimensions, got %d", len(statistics))
+	}
+
+	enutrosor := statistics[0]
+	if enutrosor.DimensionID != "enutrosor" || enutrosor.Positions != 3 {
+		t.Errorf("expected 3 positions in enutrosor, got %v in %v", enutrosor.Positions, enutrosor.DimensionID)
+	}
+	if len(enutrosor.Areas) != 2 || enutrosor.Areas[0] != (entities.Frequency{ID: "astrub", Positions: 2}) {
+		t.Errorf("unexpected areas: %v", enutrosor.Areas)
+	}
+	if enutrosor.AverageLifetime != 24*time.Hour {
+		t.Errorf("expected an average lifetime of 24h, got %v", enutrosor.AverageLifetime)
+	}
+	if enutrosor.AverageUsesPerDay != 20 {
+		t.Errorf("expected 20 uses per day, got %v", enutrosor.AverageUsesPerDay)
+	}
+	if enutrosor.CanopyFrequency != 1.0/3 {
+		t.Errorf("expected a canopy frequency of 1/3, got %v", enutrosor.CanopyFrequency)
+	}
+
+	srambad := statistics[1]
+	if srambad.Positions != 1 || srambad.AverageLifetime != 0 || srambad.AverageUsesPerDay != 0 {
+		t.Errorf("snapshots out of the period must be ignored, got %+v", srambad)
+	}
+
+	if _, err = service.GetStatistics("imagiro", "", "year"); !errors.Is(err, constants.ErrUnknownPeriod) {
+		t.Errorf("expected unknown period error, got %v", err)
+	}
+}
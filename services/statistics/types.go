@@ -0,0 +1,24 @@
+package statistics
+
+import (
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/snapshots"
+)
+
+const (
+	// frequenciesSize is the number of most frequent areas and sub-areas kept.
+	frequenciesSize = 5
+)
+
+type Service interface {
+	GetStatistics(serverID, dimensionID string, period constants.Period) ([]entities.Statistics, error)
+}
+
+// Impl returns statistics of portal positions, computed from the snapshots of their reports.
+type Impl struct {
+	now          func() time.Time
+	snapshotRepo snapshots.Repository
+}
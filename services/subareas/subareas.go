@@ -6,23 +6,38 @@ import (
 )
 
 func New(subAreaRepo subareas.Repository) (*Impl, error) {
-	subAreaEntities, err := subAreaRepo.GetSubAreas()
-	if err != nil {
+	service := Impl{
+		subAreaRepo: subAreaRepo,
+	}
+
+	if err := service.Reload(); err != nil {
 		return nil, err
 	}
 
+	return &service, nil
+}
+
+// Reload loads sub-areas again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	subAreaEntities, err := service.subAreaRepo.GetSubAreas()
+	if err != nil {
+		return err
+	}
+
 	subAreas := make(map[string]entities.SubArea)
 	for _, subArea := range subAreaEntities {
 		subAreas[subArea.DofusPortalsID] = subArea
 	}
 
-	return &Impl{
-		subAreas:    subAreas,
-		subAreaRepo: subAreaRepo,
-	}, nil
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.subAreas = subAreas
+	return nil
 }
 
 func (service *Impl) FindSubAreaByDofusPortalsID(dofusPortalsID string) (entities.SubArea, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	subArea, found := service.subAreas[dofusPortalsID]
 	return subArea, found
 }
@@ -1,15 +1,19 @@
 package subareas
 
 import (
+	"sync"
+
 	"github.com/kaellybot/kaelly-portals/models/entities"
 	"github.com/kaellybot/kaelly-portals/repositories/subareas"
 )
 
 type Service interface {
 	FindSubAreaByDofusPortalsID(dofusPortalsID string) (entities.SubArea, bool)
+	Reload() error
 }
 
 type Impl struct {
+	mutex       sync.RWMutex
 	subAreas    map[string]entities.SubArea
 	subAreaRepo subareas.Repository
 }
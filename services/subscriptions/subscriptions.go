@@ -0,0 +1,166 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/subscriptions"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
+	"github.com/rs/zerolog/log"
+)
+
+func New(broker amqp.MessageBroker, subscriptionRepo subscriptions.Repository) (*Impl, error) {
+	service := Impl{
+		broker:           broker,
+		subscriptionRepo: subscriptionRepo,
+	}
+
+	if err := service.load(); err != nil {
+		return nil, err
+	}
+
+	return &service, nil
+}
+
+func (service *Impl) Subscribe(subscription entities.Subscription) (entities.Subscription, error) {
+	if subscription.GuildID == "" || subscription.ChannelID == "" || subscription.ServerID == "" {
+		return entities.Subscription{}, errMissingField
+	}
+
+	if subscription.MinRemainingUses < 0 {
+		return entities.Subscription{}, errInvalidMinRemainingUse
+	}
+
+	subscription.ID = 0
+	if err := service.subscriptionRepo.SaveSubscription(&subscription); err != nil {
+		return entities.Subscription{}, err
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.subscriptions = append(service.subscriptions, subscription)
+	return subscription, nil
+}
+
+func (service *Impl) Unsubscribe(id uint) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for i, subscription := range service.subscriptions {
+		if subscription.ID == id {
+			if err := service.subscriptionRepo.DeleteSubscription(id); err != nil {
+				return err
+			}
+
+			service.subscriptions = append(service.subscriptions[:i], service.subscriptions[i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %d", errSubscriptionNotFound, id)
+}
+
+func (service *Impl) GetSubscriptions() []entities.Subscription {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+	return append([]entities.Subscription{}, service.subscriptions...)
+}
+
+// GetSubscribedServerIDs reloads subscriptions from the database, since they can
+// be managed by another process, and returns the servers having at least one of them.
+func (service *Impl) GetSubscribedServerIDs() ([]string, error) {
+	if err := service.load(); err != nil {
+		return nil, err
+	}
+
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	serverIDs := make(map[string]struct{})
+	for _, subscription := range service.subscriptions {
+		serverIDs[subscription.ServerID] = struct{}{}
+	}
+
+	result := make([]string, 0, len(serverIDs))
+	for serverID := range serverIDs {
+		result = append(result, serverID)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// Notify emits an alert for each subscription matching a changed portal position.
+// Alerts are emitted on the news exchange, with "guildID/channelID" as correlation ID.
+func (service *Impl) Notify(ctx context.Context, _, current *amqp.PortalPositionAnswer_PortalPosition) {
+	_, span := insights.StartSpan(ctx, "subscriptions.notify",
+		insights.AttributeServerID.String(current.GetServerId()),
+		insights.AttributeDimensionID.String(current.GetDimensionId()))
+	defer span.End()
+
+	message := &amqp.RabbitMQMessage{
+		Type:     amqp.RabbitMQMessage_PORTAL_POSITION_ANSWER,
+		Status:   amqp.RabbitMQMessage_SUCCESS,
+		Language: amqp.Language_ANY,
+		PortalPositionAnswer: &amqp.PortalPositionAnswer{
+			Positions: []*amqp.PortalPositionAnswer_PortalPosition{current},
+		},
+	}
+
+	for _, subscription := range service.GetSubscriptions() {
+		if !matches(subscription, current) {
+			continue
+		}
+
+		correlationID := fmt.Sprintf("%s/%s", subscription.GuildID, subscription.ChannelID)
+		if err := service.broker.Emit(message, amqp.ExchangeNews, alertsRoutingKey, correlationID); err != nil {
+			log.Error().Err(err).
+				Uint(constants.LogSubscriptionID, subscription.ID).
+				Msgf("Cannot emit portal alert, alert ignored")
+			continue
+		}
+
+		insights.PortalAlerts.Inc()
+	}
+}
+
+func (service *Impl) load() error {
+	subscriptions, err := service.subscriptionRepo.GetSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.subscriptions = subscriptions
+	return nil
+}
+
+func matches(subscription entities.Subscription, position *amqp.PortalPositionAnswer_PortalPosition) bool {
+	if subscription.ServerID != position.GetServerId() {
+		return false
+	}
+
+	if subscription.DimensionID != "" && subscription.DimensionID != position.GetDimensionId() {
+		return false
+	}
+
+	if position.GetPosition() == nil {
+		return false
+	}
+
+	if subscription.MinRemainingUses > 0 && position.GetRemainingUses() < subscription.MinRemainingUses {
+		return false
+	}
+
+	if subscription.TransportTypeID != "" &&
+		subscription.TransportTypeID != position.GetPosition().GetTransport().GetTypeId() {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,162 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/mocks/brokers"
+	mocksubscriptions "github.com/kaellybot/kaelly-portals/mocks/subscriptions"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+)
+
+func newPosition(serverID, dimensionID string, remainingUses int64,
+	transportTypeID string) *amqp.PortalPositionAnswer_PortalPosition {
+	return &amqp.PortalPositionAnswer_PortalPosition{
+		ServerId:      serverID,
+		DimensionId:   dimensionID,
+		RemainingUses: remainingUses,
+		Position: &amqp.PortalPositionAnswer_PortalPosition_Position{
+			X: 1,
+			Y: 2,
+			Transport: &amqp.PortalPositionAnswer_PortalPosition_Position_Transport{
+				TypeId: transportTypeID,
+			},
+		},
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name         string
+		subscription entities.Subscription
+		position     *amqp.PortalPositionAnswer_PortalPosition
+		expected     bool
+	}{
+		{
+			name:         "any dimension",
+			subscription: entities.Subscription{ServerID: "imagiro"},
+			position:     newPosition("imagiro", "enutrosor", 10, "zaap"),
+			expected:     true,
+		},
+		{
+			name:         "other server",
+			subscription: entities.Subscription{ServerID: "imagiro"},
+			position:     newPosition("draconiros", "enutrosor", 10, "zaap"),
+		},
+		{
+			name:         "other dimension",
+			subscription: entities.Subscription{ServerID: "imagiro", DimensionID: "srambad"},
+			position:     newPosition("imagiro", "enutrosor", 10, "zaap"),
+		},
+		{
+			name:         "not enough uses",
+			subscription: entities.Subscription{ServerID: "imagiro", MinRemainingUses: 50},
+			position:     newPosition("imagiro", "enutrosor", 49, "zaap"),
+		},
+		{
+			name:         "enough uses and transport",
+			subscription: entities.Subscription{ServerID: "imagiro", MinRemainingUses: 50, TransportTypeID: "zaap"},
+			position:     newPosition("imagiro", "enutrosor", 50, "zaap"),
+			expected:     true,
+		},
+		{
+			name:         "other transport",
+			subscription: entities.Subscription{ServerID: "imagiro", TransportTypeID: "zaap"},
+			position:     newPosition("imagiro", "enutrosor", 50, "zaapi"),
+		},
+		{
+			name:         "unknown position",
+			subscription: entities.Subscription{ServerID: "imagiro"},
+			position:     &amqp.PortalPositionAnswer_PortalPosition{ServerId: "imagiro", DimensionId: "enutrosor"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := matches(test.subscription, test.position); result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	repo := mocksubscriptions.New()
+	service, err := New(brokers.New(), repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = service.Subscribe(entities.Subscription{GuildID: "guild"}); !errors.Is(err, errMissingField) {
+		t.Errorf("expected %v, got %v", errMissingField, err)
+	}
+
+	subscription, err := service.Subscribe(entities.Subscription{GuildID: "guild", ChannelID: "channel",
+		ServerID: "imagiro"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved, _ := repo.GetSubscriptions(); len(saved) != 1 || saved[0].ID != subscription.ID {
+		t.Errorf("subscription not saved: %+v", saved)
+	}
+
+	if err = service.Unsubscribe(subscription.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = service.Unsubscribe(subscription.ID); !errors.Is(err, errSubscriptionNotFound) {
+		t.Errorf("expected %v, got %v", errSubscriptionNotFound, err)
+	}
+	if saved, _ := repo.GetSubscriptions(); len(saved) != 0 {
+		t.Errorf("subscription not deleted: %+v", saved)
+	}
+}
+
+func TestGetSubscribedServerIDsReloads(t *testing.T) {
+	repo := mocksubscriptions.New(entities.Subscription{GuildID: "guild", ChannelID: "channel", ServerID: "imagiro"})
+	service, err := New(brokers.New(), repo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Subscriptions managed by another process, such as an operator command.
+	_ = repo.SaveSubscription(&entities.Subscription{GuildID: "guild", ChannelID: "other", ServerID: "draconiros"})
+	_ = repo.SaveSubscription(&entities.Subscription{GuildID: "other", ChannelID: "channel", ServerID: "imagiro"})
+
+	serverIDs, err := service.GetSubscribedServerIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(serverIDs) != 2 || serverIDs[0] != "draconiros" || serverIDs[1] != "imagiro" {
+		t.Errorf("unexpected servers: %v", serverIDs)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	broker := brokers.New()
+	service, err := New(broker, mocksubscriptions.New(
+		entities.Subscription{GuildID: "guild", ChannelID: "zaap", ServerID: "imagiro", TransportTypeID: "zaap"},
+		entities.Subscription{GuildID: "guild", ChannelID: "uses", ServerID: "imagiro", MinRemainingUses: 50},
+		entities.Subscription{GuildID: "other", ChannelID: "channel", ServerID: "draconiros"},
+	))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	position := newPosition("imagiro", "enutrosor", 10, "zaap")
+	service.Notify(context.Background(), nil, position)
+
+	emissions := broker.Emissions()
+	if len(emissions) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(emissions))
+	}
+	if emissions[0].CorrelationID != "guild/zaap" || emissions[0].Exchange != amqp.ExchangeNews ||
+		emissions[0].RoutingKey != alertsRoutingKey {
+		t.Errorf("unexpected alert: %+v", emissions[0])
+	}
+	positions := emissions[0].Message.GetPortalPositionAnswer().GetPositions()
+	if len(positions) != 1 || positions[0] != position {
+		t.Errorf("unexpected positions: %v", positions)
+	}
+}
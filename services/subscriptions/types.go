@@ -0,0 +1,36 @@
+package subscriptions
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/repositories/subscriptions"
+)
+
+const (
+	alertsRoutingKey = "news.portals"
+)
+
+var (
+	errMissingField           = errors.New("guild, channel and server are required")
+	errSubscriptionNotFound   = errors.New("subscription not found")
+	errInvalidMinRemainingUse = errors.New("minimum remaining uses cannot be negative")
+)
+
+type Service interface {
+	Subscribe(subscription entities.Subscription) (entities.Subscription, error)
+	Unsubscribe(id uint) error
+	GetSubscriptions() []entities.Subscription
+	GetSubscribedServerIDs() ([]string, error)
+	Notify(ctx context.Context, previous, current *amqp.PortalPositionAnswer_PortalPosition)
+}
+
+type Impl struct {
+	mutex            sync.RWMutex
+	subscriptions    []entities.Subscription
+	broker           amqp.MessageBroker
+	subscriptionRepo subscriptions.Repository
+}
@@ -6,23 +6,38 @@ import (
 )
 
 func New(transportTypeRepo transports.Repository) (*Impl, error) {
-	transportTypeEntities, err := transportTypeRepo.GetTransportTypes()
-	if err != nil {
+	service := Impl{
+		transportTypeRepo: transportTypeRepo,
+	}
+
+	if err := service.Reload(); err != nil {
 		return nil, err
 	}
 
+	return &service, nil
+}
+
+// Reload loads transport types again from the repository, keeping the current ones on failure.
+func (service *Impl) Reload() error {
+	transportTypeEntities, err := service.transportTypeRepo.GetTransportTypes()
+	if err != nil {
+		return err
+	}
+
 	transportTypes := make(map[string]entities.TransportType)
 	for _, transportType := range transportTypeEntities {
 		transportTypes[transportType.DofusPortalsID] = transportType
 	}
 
-	return &Impl{
-		transportTypes:    transportTypes,
-		transportTypeRepo: transportTypeRepo,
-	}, nil
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	service.transportTypes = transportTypes
+	return nil
 }
 
 func (service *Impl) FindTransportTypeByDofusPortalsID(dofusPortalsID string) (entities.TransportType, bool) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
 	transportType, found := service.transportTypes[dofusPortalsID]
 	return transportType, found
 }
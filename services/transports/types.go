@@ -1,15 +1,19 @@
 package transports
 
 import (
+	"sync"
+
 	"github.com/kaellybot/kaelly-portals/models/entities"
 	"github.com/kaellybot/kaelly-portals/repositories/transports"
 )
 
 type Service interface {
 	FindTransportTypeByDofusPortalsID(dofusPortalsID string) (entities.TransportType, bool)
+	Reload() error
 }
 
 type Impl struct {
+	mutex             sync.RWMutex
 	transportTypes    map[string]entities.TransportType
 	transportTypeRepo transports.Repository
 }
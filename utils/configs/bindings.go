@@ -0,0 +1,74 @@
+package configs
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+)
+
+// ParseRequestBindings reads comma-separated bindings formatted as
+// queue:routingKey[:workers[:source+source...]].
+func ParseRequestBindings(value string) ([]RequestBinding, error) {
+	bindings := make([]RequestBinding, 0)
+	queues := make(map[string]struct{})
+	for _, spec := range strings.Split(value, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		binding, err := parseRequestBinding(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, found := queues[binding.Queue]; found {
+			return nil, fmt.Errorf("%w: %q", errDuplicatedQueue, binding.Queue)
+		}
+		queues[binding.Queue] = struct{}{}
+		bindings = append(bindings, binding)
+	}
+
+	if len(bindings) == 0 {
+		return nil, errMissingValue
+	}
+
+	return bindings, nil
+}
+
+func parseRequestBinding(spec string) (RequestBinding, error) {
+	fields := strings.Split(spec, ":")
+	if len(fields) < bindingMinFields || len(fields) > bindingMaxFields || fields[0] == "" || fields[1] == "" {
+		return RequestBinding{}, fmt.Errorf("%w: %q", errInvalidBinding, spec)
+	}
+
+	binding := RequestBinding{Queue: fields[0], RoutingKey: fields[1]}
+	if len(fields) > bindingWorkersField && fields[bindingWorkersField] != "" {
+		workers, err := strconv.Atoi(fields[bindingWorkersField])
+		if err != nil || workers < 0 {
+			return RequestBinding{}, fmt.Errorf("%w: %q", errInvalidWorkers, spec)
+		}
+		binding.Workers = workers
+	}
+
+	if len(fields) > bindingSourcesField && fields[bindingSourcesField] != "" {
+		for _, source := range strings.Split(fields[bindingSourcesField], "+") {
+			if !slices.ContainsFunc(constants.GetSources(), func(known constants.Source) bool {
+				return known.Name == source
+			}) {
+				return RequestBinding{}, fmt.Errorf("%w: %q", errUnknownSource, source)
+			}
+			binding.Sources = append(binding.Sources, source)
+		}
+	}
+
+	return binding, nil
+}
+
+// Accepts tells if portals can be retrieved from the source for this binding.
+func (binding RequestBinding) Accepts(source string) bool {
+	return len(binding.Sources) == 0 || slices.Contains(binding.Sources, source)
+}
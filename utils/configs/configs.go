@@ -0,0 +1,399 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// Load reads the configuration from viper and validates it.
+// Every invalid value is reported at once.
+func Load() (Config, error) {
+	errs := make([]error, 0)
+
+	logLevel, err := zerolog.ParseLevel(viper.GetString(constants.LogLevel))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w: %w", constants.LogLevel, errInvalidLogLevel, err))
+	}
+
+	requestBindings, err := ParseRequestBindings(viper.GetString(constants.RequestBindings))
+	if err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", constants.RequestBindings, err))
+	}
+
+	config := Config{
+		MySQLURL:                       viper.GetString(constants.MySQLURL),
+		MySQLUser:                      viper.GetString(constants.MySQLUser),
+		MySQLPassword:                  viper.GetString(constants.MySQLPassword),
+		MySQLDatabase:                  viper.GetString(constants.MySQLDatabase),
+		RabbitMQAddress:                viper.GetString(constants.RabbitMQAddress),
+		DofusPortalsEnabled:            viper.GetBool(constants.DofusPortalsEnabled),
+		DofusPortalsURL:                viper.GetString(constants.DofusPortalsURL),
+		DofusPortalsFallbackURLs:       viper.GetString(constants.DofusPortalsFallbackURLs),
+		DofusPortalsToken:              viper.GetString(constants.DofusPortalsToken),
+		DofusPortalsTokenFile:          viper.GetString(constants.DofusPortalsTokenFile),
+		DofusPortalsSecondaryToken:     viper.GetString(constants.DofusPortalsSecondaryToken),
+		DofusPortalsSecondaryTokenFile: viper.GetString(constants.DofusPortalsSecondaryTokenFile),
+		HTTPTimeout:                    viper.GetDuration(constants.DofusPortalsTimeout),
+		HTTPMode:                       viper.GetString(constants.DofusPortalsHTTPMode),
+		HTTPFixturesDir:                viper.GetString(constants.DofusPortalsFixtures),
+		DeduplicationTTL:               viper.GetDuration(constants.DeduplicationTTL),
+		DeadLetterMaxAttempts:          viper.GetInt(constants.DeadLetterMaxAttempts),
+		DeadLetterRetention:            viper.GetDuration(constants.DeadLetterRetention),
+		DeadLetterMaxEntries:           viper.GetInt(constants.DeadLetterMaxEntries),
+		QuotaUserLimit:                 viper.GetInt(constants.QuotaUserLimit),
+		QuotaWindow:                    viper.GetDuration(constants.QuotaWindow),
+		QuotaMode:                      viper.GetString(constants.QuotaMode),
+		PollInterval:                   viper.GetDuration(constants.PollInterval),
+		PositionMaxAge:                 viper.GetDuration(constants.PositionMaxAge),
+		SuspiciousPositionMode:         viper.GetString(constants.SuspiciousPositionMode),
+		StartupRetries:                 viper.GetInt(constants.StartupRetries),
+		StartupRetryDelay:              viper.GetDuration(constants.StartupRetryDelay),
+		ReferenceSnapshotFile:          viper.GetString(constants.ReferenceSnapshotFile),
+		ReferenceSource:                viper.GetString(constants.ReferenceSource),
+		ShutdownGracePeriod:            viper.GetDuration(constants.ShutdownGracePeriod),
+		ProbePort:                      viper.GetInt(constants.ProbePort),
+		MetricPort:                     viper.GetInt(constants.MetricPort),
+		RequestBindings:                requestBindings,
+		APIEnabled:                     viper.GetBool(constants.APIEnabled),
+		APIPort:                        viper.GetInt(constants.APIPort),
+		AdminToken:                     viper.GetString(constants.AdminToken),
+		AdminQueue:                     viper.GetString(constants.AdminQueue),
+		AdminRoutingKey:                viper.GetString(constants.AdminRoutingKey),
+		TracingEnabled:                 viper.GetBool(constants.TracingEnabled),
+		TracingEndpoint:                viper.GetString(constants.TracingEndpoint),
+		TracingInsecure:                viper.GetBool(constants.TracingInsecure),
+		TracingSampleRatio:             viper.GetFloat64(constants.TracingSampleRatio),
+		LogLevel:                       logLevel,
+		Production:                     viper.GetBool(constants.Production),
+	}
+
+	errs = append(errs, config.validate()...)
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+
+	return config, nil
+}
+
+// GetRuntime reads and validates the configuration from viper,
+// and returns the values that can be changed at runtime.
+func GetRuntime() (Runtime, error) {
+	config, err := Load()
+	if err != nil {
+		return Runtime{}, err
+	}
+
+	return config.Runtime(), nil
+}
+
+// Runtime returns the values of the configuration that can be changed at runtime.
+func (config Config) Runtime() Runtime {
+	return Runtime{
+		HTTPTimeout:         config.HTTPTimeout,
+		DeduplicationTTL:    config.DeduplicationTTL,
+		PollInterval:        config.PollInterval,
+		LogLevel:            config.LogLevel,
+		DofusPortalsEnabled: config.DofusPortalsEnabled,
+	}
+}
+
+// LogSummary logs the effective configuration, secrets being redacted.
+func (config Config) LogSummary() {
+	log.Info().
+		Str(constants.MySQLURL, config.MySQLURL).
+		Str(constants.MySQLUser, config.MySQLUser).
+		Str(constants.MySQLPassword, redact(config.MySQLPassword)).
+		Str(constants.MySQLDatabase, config.MySQLDatabase).
+		Str(constants.RabbitMQAddress, redactURL(config.RabbitMQAddress)).
+		Bool(constants.DofusPortalsEnabled, config.DofusPortalsEnabled).
+		Str(constants.DofusPortalsURL, config.DofusPortalsURL).
+		Str(constants.DofusPortalsFallbackURLs, config.DofusPortalsFallbackURLs).
+		Str(constants.DofusPortalsToken, redact(config.DofusPortalsToken)).
+		Str(constants.DofusPortalsTokenFile, config.DofusPortalsTokenFile).
+		Str(constants.DofusPortalsSecondaryToken, redact(config.DofusPortalsSecondaryToken)).
+		Str(constants.DofusPortalsSecondaryTokenFile, config.DofusPortalsSecondaryTokenFile).
+		Dur(constants.DofusPortalsTimeout, config.HTTPTimeout).
+		Str(constants.DofusPortalsHTTPMode, config.HTTPMode).
+		Str(constants.DofusPortalsFixtures, config.HTTPFixturesDir).
+		Dur(constants.DeduplicationTTL, config.DeduplicationTTL).
+		Int(constants.DeadLetterMaxAttempts, config.DeadLetterMaxAttempts).
+		Dur(constants.DeadLetterRetention, config.DeadLetterRetention).
+		Int(constants.DeadLetterMaxEntries, config.DeadLetterMaxEntries).
+		Int(constants.QuotaUserLimit, config.QuotaUserLimit).
+		Dur(constants.QuotaWindow, config.QuotaWindow).
+		Str(constants.QuotaMode, config.QuotaMode).
+		Dur(constants.PollInterval, config.PollInterval).
+		Dur(constants.PositionMaxAge, config.PositionMaxAge).
+		Str(constants.SuspiciousPositionMode, config.SuspiciousPositionMode).
+		Int(constants.StartupRetries, config.StartupRetries).
+		Dur(constants.StartupRetryDelay, config.StartupRetryDelay).
+		Str(constants.ReferenceSnapshotFile, config.ReferenceSnapshotFile).
+		Str(constants.ReferenceSource, config.ReferenceSource).
+		Dur(constants.ShutdownGracePeriod, config.ShutdownGracePeriod).
+		Int(constants.ProbePort, config.ProbePort).
+		Int(constants.MetricPort, config.MetricPort).
+		Interface(constants.RequestBindings, config.RequestBindings).
+		Bool(constants.APIEnabled, config.APIEnabled).
+		Int(constants.APIPort, config.APIPort).
+		Str(constants.AdminToken, redact(config.AdminToken)).
+		Str(constants.AdminQueue, config.AdminQueue).
+		Str(constants.AdminRoutingKey, config.AdminRoutingKey).
+		Bool(constants.TracingEnabled, config.TracingEnabled).
+		Str(constants.TracingEndpoint, config.TracingEndpoint).
+		Bool(constants.TracingInsecure, config.TracingInsecure).
+		Float64(constants.TracingSampleRatio, config.TracingSampleRatio).
+		Str(constants.LogLevel, config.LogLevel.String()).
+		Bool(constants.Production, config.Production).
+		Msgf("Effective configuration")
+}
+
+func (config Config) validate() []error {
+	errs := make([]error, 0)
+	if config.HTTPTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.DofusPortalsTimeout,
+			errInvalidHTTPTimeout, config.HTTPTimeout))
+	}
+
+	if config.DeduplicationTTL < 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.DeduplicationTTL,
+			errInvalidDuration, config.DeduplicationTTL))
+	}
+
+	if config.PollInterval < 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.PollInterval,
+			errInvalidDuration, config.PollInterval))
+	}
+
+	if config.PositionMaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.PositionMaxAge,
+			errInvalidMaxAge, config.PositionMaxAge))
+	}
+
+	if config.SuspiciousPositionMode != constants.SuspiciousModeAnnotate &&
+		config.SuspiciousPositionMode != constants.SuspiciousModeDrop {
+		errs = append(errs, fmt.Errorf("%s: %w: %q", constants.SuspiciousPositionMode,
+			errInvalidSuspicious, config.SuspiciousPositionMode))
+	}
+
+	if config.DeadLetterMaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %d", constants.DeadLetterMaxAttempts,
+			errInvalidAttempts, config.DeadLetterMaxAttempts))
+	}
+
+	if config.DeadLetterRetention <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.DeadLetterRetention,
+			errInvalidRetention, config.DeadLetterRetention))
+	}
+
+	if config.DeadLetterMaxEntries <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %d", constants.DeadLetterMaxEntries,
+			errInvalidMaxEntries, config.DeadLetterMaxEntries))
+	}
+
+	for key, value := range map[string]string{
+		constants.MySQLURL:      config.MySQLURL,
+		constants.MySQLDatabase: config.MySQLDatabase,
+	} {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s: %w", key, errMissingValue))
+		}
+	}
+
+	if !isValidURL(config.RabbitMQAddress, "amqp", "amqps") {
+		errs = append(errs, fmt.Errorf("%s: %w", constants.RabbitMQAddress, errInvalidAMQPAddress))
+	}
+
+	errs = append(errs, config.validateDofusPortals()...)
+	errs = append(errs, config.validatePorts()...)
+	errs = append(errs, config.validateTracing()...)
+	errs = append(errs, config.validateQuotas()...)
+	errs = append(errs, config.validateLifecycle()...)
+	return errs
+}
+
+// validateLifecycle checks the values used while starting and shutting down.
+func (config Config) validateLifecycle() []error {
+	errs := make([]error, 0)
+	if config.StartupRetries < 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %d", constants.StartupRetries,
+			errInvalidRetries, config.StartupRetries))
+	}
+
+	if config.StartupRetryDelay <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.StartupRetryDelay,
+			errInvalidRetryDelay, config.StartupRetryDelay))
+	}
+
+	if config.ReferenceSource != constants.ReferenceSourceMySQL &&
+		config.ReferenceSource != constants.ReferenceSourceFile {
+		errs = append(errs, fmt.Errorf("%s: %w: %q", constants.ReferenceSource,
+			errInvalidSource, config.ReferenceSource))
+	}
+
+	if config.ReferenceSource == constants.ReferenceSourceFile && config.ReferenceSnapshotFile == "" {
+		errs = append(errs, fmt.Errorf("%s: %w", constants.ReferenceSnapshotFile, errMissingValue))
+	}
+
+	if config.ShutdownGracePeriod <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.ShutdownGracePeriod,
+			errInvalidGracePeriod, config.ShutdownGracePeriod))
+	}
+
+	return errs
+}
+
+func (config Config) validateQuotas() []error {
+	errs := make([]error, 0)
+	if config.QuotaUserLimit < 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %d", constants.QuotaUserLimit,
+			errInvalidQuota, config.QuotaUserLimit))
+	}
+
+	if config.QuotaUserLimit > 0 && config.QuotaWindow <= 0 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.QuotaWindow,
+			errInvalidQuotaWindow, config.QuotaWindow))
+	}
+
+	if config.QuotaMode != constants.QuotaModeCache && config.QuotaMode != constants.QuotaModeReject {
+		errs = append(errs, fmt.Errorf("%s: %w: %q", constants.QuotaMode,
+			errInvalidQuotaMode, config.QuotaMode))
+	}
+
+	return errs
+}
+
+func (config Config) validateTracing() []error {
+	errs := make([]error, 0)
+	if !config.TracingEnabled {
+		return errs
+	}
+
+	if config.TracingEndpoint == "" {
+		errs = append(errs, fmt.Errorf("%s: %w", constants.TracingEndpoint, errMissingValue))
+	}
+
+	if config.TracingSampleRatio < 0 || config.TracingSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("%s: %w: %v", constants.TracingSampleRatio,
+			errInvalidSampleRatio, config.TracingSampleRatio))
+	}
+
+	return errs
+}
+
+func (config Config) validateDofusPortals() []error {
+	errs := make([]error, 0)
+	if !isValidURL(config.DofusPortalsURL, "http", "https") {
+		errs = append(errs, fmt.Errorf("%s: %w: %q", constants.DofusPortalsURL,
+			errInvalidURL, config.DofusPortalsURL))
+	}
+
+	for _, fallbackURL := range strings.Split(config.DofusPortalsFallbackURLs, ",") {
+		fallbackURL = strings.TrimSpace(fallbackURL)
+		if fallbackURL != "" && !isValidURL(fallbackURL, "http", "https") {
+			errs = append(errs, fmt.Errorf("%s: %w: %q", constants.DofusPortalsFallbackURLs,
+				errInvalidURL, fallbackURL))
+		}
+	}
+
+	for key, file := range map[string]string{
+		constants.DofusPortalsTokenFile:          config.DofusPortalsTokenFile,
+		constants.DofusPortalsSecondaryTokenFile: config.DofusPortalsSecondaryTokenFile,
+	} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w: %w", key, errTokenFile, err))
+		}
+	}
+
+	if config.HTTPMode != constants.HTTPModeLive &&
+		config.HTTPMode != constants.HTTPModeRecord && config.HTTPMode != constants.HTTPModeReplay {
+		errs = append(errs, fmt.Errorf("%s: %w: %q", constants.DofusPortalsHTTPMode,
+			errInvalidHTTPMode, config.HTTPMode))
+	}
+
+	if config.Production {
+		if config.DofusPortalsEnabled && config.DofusPortalsToken == "" && config.DofusPortalsTokenFile == "" {
+			errs = append(errs, fmt.Errorf("%s: %w", constants.DofusPortalsToken, errMissingToken))
+		}
+
+		if config.HTTPMode != constants.HTTPModeLive {
+			errs = append(errs, fmt.Errorf("%s: %w", constants.DofusPortalsHTTPMode, errProductionHTTPMode))
+		}
+	}
+
+	return errs
+}
+
+func (config Config) validatePorts() []error {
+	errs := make([]error, 0)
+	ports := map[string]int{
+		constants.ProbePort:  config.ProbePort,
+		constants.MetricPort: config.MetricPort,
+	}
+	if config.APIEnabled {
+		ports[constants.APIPort] = config.APIPort
+	}
+
+	usedPorts := make(map[int]string)
+	for _, key := range []string{constants.ProbePort, constants.MetricPort, constants.APIPort} {
+		port, found := ports[key]
+		if !found {
+			continue
+		}
+
+		if port < minPort || port > maxPort {
+			errs = append(errs, fmt.Errorf("%s: %w: %d", key, errInvalidPort, port))
+		} else if otherKey, used := usedPorts[port]; used {
+			errs = append(errs, fmt.Errorf("%s: %w: %d used by %s", key, errDuplicatedPort, port, otherKey))
+		}
+		usedPorts[port] = key
+	}
+
+	return errs
+}
+
+func isValidURL(value string, schemes ...string) bool {
+	parsedURL, err := url.Parse(value)
+	if err != nil || parsedURL.Host == "" {
+		return false
+	}
+
+	for _, scheme := range schemes {
+		if parsedURL.Scheme == scheme {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+func redactURL(value string) string {
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return redacted
+	}
+
+	if parsedURL.User != nil {
+		parsedURL.User = url.UserPassword(parsedURL.User.Username(), redacted)
+	}
+
+	return parsedURL.Redacted()
+}
@@ -0,0 +1,30 @@
+package configs
+
+import (
+	"github.com/fsnotify/fsnotify"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// Watch reloads the runtime configuration each time the config file changes.
+// Listeners are only called if the new configuration is valid; otherwise, the
+// previous values are kept. Values provided through environment variables take
+// precedence over the file and therefore cannot be reloaded.
+func Watch(listeners ...Listener) {
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		runtime, err := GetRuntime()
+		if err != nil {
+			log.Error().Err(err).Str(constants.LogFileName, event.Name).
+				Msgf("Configuration reload rejected, keeping previous values")
+			return
+		}
+
+		log.Info().Str(constants.LogFileName, event.Name).Msgf("Configuration reloaded")
+		for _, listener := range listeners {
+			listener(runtime)
+		}
+	})
+
+	viper.WatchConfig()
+}
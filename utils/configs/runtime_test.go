@@ -0,0 +1,50 @@
+package configs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+)
+
+func TestGetRuntime(t *testing.T) {
+	tests := []struct {
+		name          string
+		httpTimeout   string
+		logLevel      string
+		enabled       bool
+		expectedError bool
+	}{
+		{name: "valid", httpTimeout: "10s", logLevel: "debug", enabled: true},
+		{name: "negative timeout", httpTimeout: "-10s", logLevel: "debug", expectedError: true},
+		{name: "zero timeout", httpTimeout: "0s", logLevel: "debug", expectedError: true},
+		{name: "unknown log level", httpTimeout: "10s", logLevel: "verbose", expectedError: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setDefaults()
+			viper.Set(constants.DofusPortalsTimeout, test.httpTimeout)
+			viper.Set(constants.LogLevel, test.logLevel)
+			viper.Set(constants.DofusPortalsEnabled, test.enabled)
+			viper.Set(constants.DeduplicationTTL, "1m")
+			viper.Set(constants.PollInterval, "30s")
+
+			runtime, err := GetRuntime()
+			if (err != nil) != test.expectedError {
+				t.Fatalf("expected error: %v, got %v", test.expectedError, err)
+			}
+			if test.expectedError {
+				return
+			}
+
+			if runtime.HTTPTimeout != 10*time.Second || runtime.LogLevel != zerolog.DebugLevel ||
+				runtime.DofusPortalsEnabled != test.enabled || runtime.DeduplicationTTL != time.Minute ||
+				runtime.PollInterval != 30*time.Second {
+				t.Errorf("unexpected runtime configuration: %+v", runtime)
+			}
+		})
+	}
+}
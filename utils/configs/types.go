@@ -0,0 +1,123 @@
+package configs
+
+import (
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// Fields of a request binding: queue, routing key, workers and sources.
+	bindingMinFields    = 2
+	bindingMaxFields    = 4
+	bindingWorkersField = 2
+	bindingSourcesField = 3
+
+	minPort  = 1
+	maxPort  = 65535
+	redacted = "***"
+)
+
+var (
+	errInvalidHTTPTimeout = errors.New("HTTP timeout must be strictly positive")
+	errInvalidLogLevel    = errors.New("log level cannot be parsed")
+	errInvalidHTTPMode    = errors.New("HTTP mode must be one of live, record or replay")
+	errInvalidURL         = errors.New("URL must be absolute with http or https scheme")
+	errInvalidAMQPAddress = errors.New("RabbitMQ address must be absolute with amqp or amqps scheme")
+	errInvalidPort        = errors.New("port must be between 1 and 65535")
+	errDuplicatedPort     = errors.New("port is already used by another server")
+	errMissingValue       = errors.New("value is required")
+	errTokenFile          = errors.New("token file cannot be read")
+	errInvalidDuration    = errors.New("duration cannot be negative")
+	errInvalidAttempts    = errors.New("attempts must be strictly positive")
+	errInvalidRetention   = errors.New("retention must be strictly positive")
+	errInvalidMaxEntries  = errors.New("maximum number of entries must be strictly positive")
+	errInvalidMaxAge      = errors.New("max age must be strictly positive")
+	errInvalidSuspicious  = errors.New("suspicious position mode must be one of annotate or drop")
+	errInvalidGracePeriod = errors.New("grace period must be strictly positive")
+	errInvalidRetries     = errors.New("retries cannot be negative")
+	errInvalidRetryDelay  = errors.New("retry delay must be strictly positive")
+	errInvalidSource      = errors.New("reference source must be one of mysql or file")
+	errInvalidBinding     = errors.New("binding must be formatted as queue:routingKey[:workers[:sources]]")
+	errInvalidWorkers     = errors.New("workers must be a positive number or 0")
+	errDuplicatedQueue    = errors.New("queue is bound more than once")
+	errUnknownSource      = errors.New("source is unknown")
+	errInvalidQuota       = errors.New("quota limit cannot be negative")
+	errInvalidQuotaWindow = errors.New("quota window must be strictly positive")
+	errInvalidQuotaMode   = errors.New("quota mode must be one of cache or reject")
+	errInvalidSampleRatio = errors.New("sample ratio must be between 0 and 1")
+	errMissingToken       = errors.New("production requires a Dofus Portals token when source is enabled")
+	errProductionHTTPMode = errors.New("production requires live HTTP mode")
+)
+
+// Config is the typed configuration of the application, read from viper.
+type Config struct {
+	MySQLURL                       string
+	MySQLUser                      string
+	MySQLPassword                  string
+	MySQLDatabase                  string
+	RabbitMQAddress                string
+	DofusPortalsEnabled            bool
+	DofusPortalsURL                string
+	DofusPortalsFallbackURLs       string
+	DofusPortalsToken              string
+	DofusPortalsTokenFile          string
+	DofusPortalsSecondaryToken     string
+	DofusPortalsSecondaryTokenFile string
+	HTTPTimeout                    time.Duration
+	HTTPMode                       string
+	HTTPFixturesDir                string
+	DeduplicationTTL               time.Duration
+	DeadLetterMaxAttempts          int
+	DeadLetterRetention            time.Duration
+	DeadLetterMaxEntries           int
+	QuotaUserLimit                 int
+	QuotaWindow                    time.Duration
+	QuotaMode                      string
+	PollInterval                   time.Duration
+	PositionMaxAge                 time.Duration
+	SuspiciousPositionMode         string
+	StartupRetries                 int
+	StartupRetryDelay              time.Duration
+	ReferenceSnapshotFile          string
+	ReferenceSource                string
+	ShutdownGracePeriod            time.Duration
+	ProbePort                      int
+	MetricPort                     int
+	RequestBindings                []RequestBinding
+	APIEnabled                     bool
+	APIPort                        int
+	AdminToken                     string
+	AdminQueue                     string
+	AdminRoutingKey                string
+	TracingEnabled                 bool
+	TracingEndpoint                string
+	TracingInsecure                bool
+	TracingSampleRatio             float64
+	LogLevel                       zerolog.Level
+	Production                     bool
+}
+
+// Runtime gathers the configuration values that can be changed
+// without restarting the application.
+type Runtime struct {
+	HTTPTimeout         time.Duration
+	DeduplicationTTL    time.Duration
+	PollInterval        time.Duration
+	LogLevel            zerolog.Level
+	DofusPortalsEnabled bool
+}
+
+// Listener is called with the new runtime configuration once validated.
+type Listener func(runtime Runtime)
+
+// RequestBinding binds a routing key to a queue of portal requests, treated by
+// at most Workers requests at once (0 for no limit) and answered from Sources only
+// (every source when empty).
+type RequestBinding struct {
+	Queue      string
+	RoutingKey string
+	Workers    int
+	Sources    []string
+}
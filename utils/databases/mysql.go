@@ -3,9 +3,9 @@ package databases
 import (
 	"fmt"
 
-	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/kaellybot/kaelly-portals/utils/retries"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -18,18 +18,19 @@ type MySQLConnection interface {
 }
 
 type mySQLConnection struct {
-	dsn string
-	db  *gorm.DB
+	dsn    string
+	policy retries.Policy
+	db     *gorm.DB
 }
 
-func New() MySQLConnection {
-	dbUser := viper.GetString(constants.MySQLUser)
-	dbPassword := viper.GetString(constants.MySQLPassword)
-	dbURL := viper.GetString(constants.MySQLURL)
-	dbName := viper.GetString(constants.MySQLDatabase)
+func New(config configs.Config) MySQLConnection {
 	return &mySQLConnection{
 		dsn: fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=true&loc=UTC",
-			dbUser, dbPassword, dbURL, dbName),
+			config.MySQLUser, config.MySQLPassword, config.MySQLURL, config.MySQLDatabase),
+		policy: retries.Policy{
+			Retries: config.StartupRetries,
+			Delay:   config.StartupRetryDelay,
+		},
 	}
 }
 
@@ -54,19 +55,37 @@ func (c *mySQLConnection) IsConnected() bool {
 	return true
 }
 
+// Run connects to MySQL, retrying with backoff. If MySQL is still unreachable afterwards,
+// a lazy connection is kept so that queries fail until MySQL is back instead of panicking.
 func (c *mySQLConnection) Run() error {
-	db, err := gorm.Open(mysql.Open(c.dsn), &gorm.Config{})
+	err := retries.Do("MySQL", c.policy, func() error {
+		db, errOpen := gorm.Open(mysql.Open(c.dsn), &gorm.Config{})
+		if errOpen != nil {
+			return errOpen
+		}
+
+		c.db = db
+		return nil
+	})
 	if err != nil {
+		db, errOpen := gorm.Open(mysql.New(mysql.Config{DSN: c.dsn, SkipInitializeWithVersion: true}),
+			&gorm.Config{DisableAutomaticPing: true})
+		if errOpen == nil {
+			c.db = db
+		}
 		return err
 	}
 
-	c.db = db
 	log.Info().Msg("Connected to MySQL")
 	return nil
 }
 
 func (c *mySQLConnection) Shutdown() {
 	log.Info().Msg("Shutdown the connection to MySQL")
+	if c.db == nil {
+		return
+	}
+
 	dbSQL, err := c.db.DB()
 	if err != nil {
 		log.Error().Err(err).Msgf("Failed to shutdown database connection")
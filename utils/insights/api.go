@@ -0,0 +1,289 @@
+package insights
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	amqp "github.com/kaellybot/kaelly-amqp"
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/models/entities"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type API interface {
+	ListenAndServe()
+	// Shutdown waits for the requests being served until ctx is done.
+	Shutdown(ctx context.Context)
+}
+
+type api struct {
+	server         *http.Server
+	enabled        bool
+	getPortals     GetPortalsFunc
+	score          ScorePortalFunc
+	getLeaderboard GetLeaderboardFunc
+	getStatistics  GetStatisticsFunc
+	localize       LocalizePortalFunc
+}
+
+type contribution struct {
+	Reporter      string    `json:"reporter"`
+	Contributions int64     `json:"contributions"`
+	LastReportAt  time.Time `json:"lastReportAt"`
+}
+
+type statistics struct {
+	ServerID               string      `json:"serverId"`
+	DimensionID            string      `json:"dimensionId"`
+	Positions              int         `json:"positions"`
+	Areas                  []frequency `json:"areas"`
+	SubAreas               []frequency `json:"subAreas"`
+	AverageLifetimeSeconds float64     `json:"averageLifetimeSeconds"`
+	AverageUsesPerDay      float64     `json:"averageUsesPerDay"`
+	CanopyFrequency        float64     `json:"canopyFrequency"`
+}
+
+type frequency struct {
+	ID        string `json:"id"`
+	Positions int    `json:"positions"`
+}
+
+// confidence is added to each position returned by the API.
+type confidence struct {
+	Score      float64 `json:"score"`
+	Outdated   bool    `json:"outdated"`
+	Suspicious bool    `json:"suspicious"`
+}
+
+// GetPortalsFunc retrieves mapped portal positions based on internal IDs;
+// an empty dimensionID means every dimension of the server.
+type GetPortalsFunc func(ctx context.Context, serverID, dimensionID string,
+) ([]*amqp.PortalPositionAnswer_PortalPosition, error)
+
+// ScorePortalFunc rates the confidence of a portal position from 0 to 1,
+// and tells if it is probably outdated or suspicious.
+type ScorePortalFunc func(position *amqp.PortalPositionAnswer_PortalPosition,
+) (score float64, outdated, suspicious bool)
+
+// GetLeaderboardFunc retrieves the best contributors of a server over a period;
+// an empty serverID means every server.
+type GetLeaderboardFunc func(serverID string, period constants.Period) ([]entities.Contribution, error)
+
+// GetStatisticsFunc computes the statistics of a server over a period, per dimension;
+// an empty dimensionID means every dimension.
+type GetStatisticsFunc func(serverID, dimensionID string, period constants.Period) ([]entities.Statistics, error)
+
+// LocalizePortalFunc returns ready-to-display labels of a portal position, indexed by field.
+type LocalizePortalFunc func(position *amqp.PortalPositionAnswer_PortalPosition,
+	language amqp.Language) map[string]string
+
+// NewAPI builds the HTTP API; admin routes are only served when an admin token is configured.
+func NewAPI(config configs.Config, getPortals GetPortalsFunc, score ScorePortalFunc,
+	getLeaderboard GetLeaderboardFunc, getStatistics GetStatisticsFunc, localize LocalizePortalFunc,
+	admin http.Handler) API {
+	impl := api{
+		enabled:        config.APIEnabled,
+		getPortals:     getPortals,
+		score:          score,
+		getLeaderboard: getLeaderboard,
+		getStatistics:  getStatistics,
+		localize:       localize,
+	}
+	apiMux := http.NewServeMux()
+	apiMux.HandleFunc("GET /v1/servers/{serverID}/portals", impl.portals)
+	apiMux.HandleFunc("GET /v1/servers/{serverID}/portals/{dimensionID}", impl.portals)
+	apiMux.HandleFunc("GET /v1/leaderboard", impl.leaderboard)
+	apiMux.HandleFunc("GET /v1/servers/{serverID}/leaderboard", impl.leaderboard)
+	apiMux.HandleFunc("GET /v1/servers/{serverID}/statistics", impl.statistics)
+	apiMux.HandleFunc("GET /v1/servers/{serverID}/statistics/{dimensionID}", impl.statistics)
+	if config.AdminToken != "" {
+		apiMux.Handle("/v1/admin/", admin)
+	}
+
+	impl.server = &http.Server{
+		Addr:              fmt.Sprintf(":%v", config.APIPort),
+		Handler:           apiMux,
+		ReadHeaderTimeout: 0,
+	}
+
+	return &impl
+}
+
+func (api *api) ListenAndServe() {
+	if !api.enabled {
+		log.Info().Msgf("HTTP API disabled, not exposed")
+		return
+	}
+
+	go func() {
+		log.Info().Msgf("Exposing HTTP API...")
+		err := api.server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msgf("Cannot listen and serve HTTP API")
+		}
+	}()
+}
+
+func (api *api) Shutdown(ctx context.Context) {
+	if api.enabled && api.server != nil {
+		if err := api.server.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msgf("Failed to shutdown HTTP API server")
+		}
+	}
+}
+
+func (api *api) portals(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverID")
+	dimensionID := r.PathValue("dimensionID")
+	language := amqp.Language_ANY
+	if value := r.URL.Query().Get("lang"); value != "" {
+		languageValue, found := amqp.Language_value[strings.ToUpper(value)]
+		if !found {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		language = amqp.Language(languageValue)
+	}
+
+	positions, err := api.getPortals(r.Context(), serverID, dimensionID)
+	if err != nil {
+		log.Error().Err(err).
+			Str(constants.LogServerID, serverID).
+			Str(constants.LogDimensionID, dimensionID).
+			Msgf("Cannot retrieve portals, returning failed HTTP response")
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	result := make([]json.RawMessage, 0, len(positions))
+	for _, position := range positions {
+		data, errMarshal := api.marshal(position, language)
+		if errMarshal != nil {
+			log.Error().Err(errMarshal).Msgf("Cannot marshal portal, returning failed HTTP response")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		result = append(result, data)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		log.Error().Err(err).Msgf("Cannot write HTTP response")
+	}
+}
+
+// leaderboard returns the best contributors over the period given as query parameter,
+// every snapshot being considered by default.
+func (api *api) leaderboard(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverID")
+	period, err := constants.ParsePeriod(r.URL.Query().Get("period"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	contributions, err := api.getLeaderboard(serverID, period)
+	if err != nil {
+		log.Error().Err(err).
+			Str(constants.LogServerID, serverID).
+			Msgf("Cannot retrieve leaderboard, returning failed HTTP response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]contribution, 0, len(contributions))
+	for _, entity := range contributions {
+		result = append(result, contribution{
+			Reporter:      entity.Reporter,
+			Contributions: entity.Contributions,
+			LastReportAt:  entity.LastReportAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		log.Error().Err(err).Msgf("Cannot write HTTP response")
+	}
+}
+
+// statistics returns the statistics of a server per dimension over the period given
+// as query parameter, every snapshot being considered by default.
+func (api *api) statistics(w http.ResponseWriter, r *http.Request) {
+	serverID := r.PathValue("serverID")
+	dimensionID := r.PathValue("dimensionID")
+	period, err := constants.ParsePeriod(r.URL.Query().Get("period"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	dimensions, err := api.getStatistics(serverID, dimensionID, period)
+	if err != nil {
+		log.Error().Err(err).
+			Str(constants.LogServerID, serverID).
+			Str(constants.LogDimensionID, dimensionID).
+			Msgf("Cannot compute statistics, returning failed HTTP response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]statistics, 0, len(dimensions))
+	for _, entity := range dimensions {
+		result = append(result, statistics{
+			ServerID:               entity.ServerID,
+			DimensionID:            entity.DimensionID,
+			Positions:              entity.Positions,
+			Areas:                  mapFrequencies(entity.Areas),
+			SubAreas:               mapFrequencies(entity.SubAreas),
+			AverageLifetimeSeconds: entity.AverageLifetime.Seconds(),
+			AverageUsesPerDay:      entity.AverageUsesPerDay,
+			CanopyFrequency:        entity.CanopyFrequency,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		log.Error().Err(err).Msgf("Cannot write HTTP response")
+	}
+}
+
+func mapFrequencies(frequencies []entities.Frequency) []frequency {
+	result := make([]frequency, 0, len(frequencies))
+	for _, entity := range frequencies {
+		result = append(result, frequency{ID: entity.ID, Positions: entity.Positions})
+	}
+	return result
+}
+
+// marshal encodes a position as protojson, with its confidence and its labels as additional fields.
+func (api *api) marshal(position *amqp.PortalPositionAnswer_PortalPosition,
+	language amqp.Language) (json.RawMessage, error) {
+	data, err := protojson.Marshal(position)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	score, outdated, suspicious := api.score(position)
+	fields["confidence"], err = json.Marshal(confidence{Score: score, Outdated: outdated, Suspicious: suspicious})
+	if err != nil {
+		return nil, err
+	}
+
+	fields["labels"], err = json.Marshal(api.localize(position, language))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
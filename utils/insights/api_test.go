@@ -0,0 +1,23 @@
+package insights
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+func TestAdminRoutesDisabledWithoutToken(t *testing.T) {
+	admin := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewAPI(configs.Config{}, nil, nil, nil, nil, nil, admin).(*api).server.Handler
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/admin/stats", nil))
+
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("expected status %v, got %v", http.StatusNotFound, recorder.Code)
+	}
+}
@@ -0,0 +1,86 @@
+package insights
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricNamespace = "kaelly_portals"
+
+	LabelEndpoint  = "endpoint"
+	LabelStatus    = "status"
+	LabelToken     = "token"
+	LabelMode      = "mode"
+	LabelServer    = "server"
+	LabelDimension = "dimension"
+)
+
+//nolint:gochecknoglobals // Prometheus collectors are registered once per process.
+var (
+	UpstreamRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "upstream_requests_total",
+		Help:      "Number of requests sent to dofus-portals, per endpoint and status.",
+	}, []string{LabelEndpoint, LabelStatus})
+
+	UpstreamEndpointHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "upstream_endpoint_healthy",
+		Help:      "Whether a dofus-portals endpoint is considered healthy (1) or not (0).",
+	}, []string{LabelEndpoint})
+
+	TokenSwitches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "token_switches_total",
+		Help:      "Number of switches to another dofus-portals token after a rejection, per activated token.",
+	}, []string{LabelToken})
+
+	DuplicateRequests = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "duplicate_requests_total",
+		Help:      "Number of redelivered requests answered from the replies already sent.",
+	})
+
+	DeadLetters = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "dead_letters_total",
+		Help:      "Number of failed requests recorded as dead letters, per status.",
+	}, []string{LabelStatus})
+
+	QuarantinedRequests = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "quarantined_requests",
+		Help:      "Number of requests currently quarantined, waiting to be requeued.",
+	})
+
+	RateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Number of requests over quota, per way they were answered (cache or reject).",
+	}, []string{LabelMode})
+
+	PortalAlerts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "portal_alerts_total",
+		Help:      "Number of alerts emitted to subscribed channels after a portal change.",
+	})
+
+	Polls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "polls_total",
+		Help:      "Number of portal polls of subscribed servers, per status.",
+	}, []string{LabelStatus})
+
+	OutdatedPositions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Name:      "outdated_positions",
+		Help:      "Portal positions older than the configured max age when last retrieved, per server and dimension.",
+	}, []string{LabelServer, LabelDimension})
+
+	SuspiciousPositions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Name:      "suspicious_positions_total",
+		Help:      "Number of retrieved portal positions failing map bounds validation, per way they were handled.",
+	}, []string{LabelMode})
+)
@@ -4,34 +4,50 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
-	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 )
 
+// degradedBody is answered by /ready when serving while a dependency is down.
+const degradedBody = "degraded"
+
 type Probes interface {
 	ListenAndServe()
+	// SetStarted reports the startup as over, once connections are up and reference data is loaded.
+	SetStarted()
+	// SetReady forces readiness to false while shutting down, whatever connections are up.
+	SetReady(ready bool)
 	Shutdown()
 }
 
 type probes struct {
-	server       *http.Server
-	isReadyFuncs []IsReadyFunc
+	server         *http.Server
+	isReadyFuncs   []IsReadyFunc
+	isDegradedFunc IsDegradedFunc
+	isStarted      atomic.Bool
+	isReady        atomic.Bool
 }
 
 type IsReadyFunc func() bool
 
-func NewProbes(isReadyFuncs ...IsReadyFunc) Probes {
+// IsDegradedFunc tells if the service is still serving while a dependency is down.
+type IsDegradedFunc func() bool
+
+func NewProbes(config configs.Config, isDegradedFunc IsDegradedFunc, isReadyFuncs ...IsReadyFunc) Probes {
 	impl := probes{
-		isReadyFuncs: isReadyFuncs,
+		isReadyFuncs:   isReadyFuncs,
+		isDegradedFunc: isDegradedFunc,
 	}
+	impl.isReady.Store(true)
 	probesMux := http.NewServeMux()
+	probesMux.HandleFunc("/startup", impl.startup)
 	probesMux.HandleFunc("/live", impl.live)
 	probesMux.HandleFunc("/ready", impl.ready)
 
 	impl.server = &http.Server{
-		Addr:              fmt.Sprintf(":%v", viper.GetInt(constants.ProbePort)),
+		Addr:              fmt.Sprintf(":%v", config.ProbePort),
 		Handler:           probesMux,
 		ReadHeaderTimeout: 0,
 	}
@@ -49,6 +65,14 @@ func (probes *probes) ListenAndServe() {
 	}()
 }
 
+func (probes *probes) SetStarted() {
+	probes.isStarted.Store(true)
+}
+
+func (probes *probes) SetReady(ready bool) {
+	probes.isReady.Store(ready)
+}
+
 func (probes *probes) Shutdown() {
 	if probes.server != nil {
 		if err := probes.server.Shutdown(context.Background()); err != nil {
@@ -57,20 +81,34 @@ func (probes *probes) Shutdown() {
 	}
 }
 
+func (probes *probes) startup(w http.ResponseWriter, _ *http.Request) {
+	if probes.isStarted.Load() {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
+
 func (probes *probes) live(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
 func (probes *probes) ready(w http.ResponseWriter, _ *http.Request) {
-	isReady := true
+	isReady := probes.isStarted.Load() && probes.isReady.Load()
 
 	for _, isReadyFunc := range probes.isReadyFuncs {
 		isReady = isReady && checkReadiness(isReadyFunc)
 	}
 
-	if isReady {
+	switch {
+	case isReady && checkReadiness(IsReadyFunc(probes.isDegradedFunc)):
 		w.WriteHeader(http.StatusOK)
-	} else {
+		if _, err := w.Write([]byte(degradedBody)); err != nil {
+			log.Error().Err(err).Msgf("Cannot write readiness")
+		}
+	case isReady:
+		w.WriteHeader(http.StatusOK)
+	default:
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 }
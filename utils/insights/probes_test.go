@@ -0,0 +1,45 @@
+package insights
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+)
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		started    bool
+		ready      bool
+		degraded   bool
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "not started", ready: true, wantStatus: http.StatusServiceUnavailable},
+		{name: "dependency down", started: true, wantStatus: http.StatusServiceUnavailable},
+		{name: "ready", started: true, ready: true, wantStatus: http.StatusOK},
+		{name: "degraded", started: true, ready: true, degraded: true, wantStatus: http.StatusOK, wantBody: degradedBody},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			impl, ok := NewProbes(configs.Config{},
+				func() bool { return test.degraded },
+				func() bool { return test.ready }).(*probes)
+			if !ok {
+				t.Fatal("unexpected probes implementation")
+			}
+			if test.started {
+				impl.SetStarted()
+			}
+
+			recorder := httptest.NewRecorder()
+			impl.ready(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			if recorder.Code != test.wantStatus || recorder.Body.String() != test.wantBody {
+				t.Errorf("expected %d %q, got %d %q", test.wantStatus, test.wantBody, recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
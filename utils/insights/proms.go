@@ -5,10 +5,9 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
 )
 
 type PrometheusMetrics interface {
@@ -20,12 +19,12 @@ type prom struct {
 	server *http.Server
 }
 
-func NewPrometheusMetrics() PrometheusMetrics {
+func NewPrometheusMetrics(config configs.Config) PrometheusMetrics {
 	metricsMux := http.NewServeMux()
 	metricsMux.Handle("/metrics", promhttp.Handler())
 	return &prom{
 		server: &http.Server{
-			Addr:              fmt.Sprintf(":%v", viper.GetInt(constants.MetricPort)),
+			Addr:              fmt.Sprintf(":%v", config.MetricPort),
 			Handler:           metricsMux,
 			ReadHeaderTimeout: 0,
 		},
@@ -0,0 +1,137 @@
+package insights
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"github.com/kaellybot/kaelly-portals/utils/recorders"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/kaellybot/kaelly-portals"
+
+	AttributeCorrelationID = attribute.Key("messaging.message.conversation_id")
+	AttributeServerID      = attribute.Key("kaelly.server.id")
+	AttributeDimensionID   = attribute.Key("kaelly.dimension.id")
+)
+
+type Traces interface {
+	Shutdown()
+}
+
+type traces struct {
+	provider *sdktrace.TracerProvider
+}
+
+type tracingDoer struct {
+	doer recorders.HTTPRequestDoer
+}
+
+// NewTraces exports spans to an OTLP collector over HTTP when tracing is enabled.
+// Otherwise, spans are not recorded at all.
+func NewTraces(config configs.Config) (Traces, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if !config.TracingEnabled {
+		return &traces{}, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.TracingEndpoint)}
+	if config.TracingInsecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTraces(sdktrace.NewBatchSpanProcessor(exporter),
+		sdktrace.TraceIDRatioBased(config.TracingSampleRatio)), nil
+}
+
+func newTraces(processor sdktrace.SpanProcessor, sampler sdktrace.Sampler) *traces {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(constants.InternalName),
+			semconv.ServiceVersion(constants.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return &traces{provider: provider}
+}
+
+func (traces *traces) Shutdown() {
+	if traces.provider != nil {
+		if err := traces.provider.Shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msgf("Failed to flush traces")
+		}
+	}
+}
+
+// StartSpan starts a span as a child of the one held by ctx, if any.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records err on the span, if any, before ending it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ExtractContext returns a context holding the W3C trace context carried by headers.
+func ExtractContext(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// NewTracingDoer wraps doer to trace each HTTP request and propagate
+// the W3C trace context to the remote server.
+func NewTracingDoer(doer recorders.HTTPRequestDoer) recorders.HTTPRequestDoer {
+	return &tracingDoer{doer: doer}
+}
+
+func (doer *tracingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.URLFull(req.URL.Redacted()),
+			semconv.ServerAddress(req.URL.Hostname()),
+		))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := doer.doer.Do(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,107 @@
+package insights
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kaellybot/kaelly-portals/utils/configs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+func TestNewTracesExportsToCollector(t *testing.T) {
+	received := make(chan string, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		received <- r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	collectorURL, err := url.Parse(collector.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err := NewTraces(configs.Config{
+		TracingEnabled:     true,
+		TracingEndpoint:    collectorURL.Host,
+		TracingInsecure:    true,
+		TracingSampleRatio: 1.0,
+	})
+	if err != nil {
+		t.Fatalf("cannot build traces: %v", err)
+	}
+
+	_, span := StartSpan(context.Background(), "test")
+	span.End()
+	traces.Shutdown()
+
+	select {
+	case path := <-received:
+		if path != "/v1/traces" {
+			t.Errorf("unexpected collector path: %v", path)
+		}
+	default:
+		t.Error("no span exported to the collector")
+	}
+}
+
+func TestTracingDoer(t *testing.T) {
+	exporter := newTestTraces(t)
+
+	var header http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer upstream.Close()
+
+	ctx := ExtractContext(context.Background(), map[string]string{"traceparent": traceParent})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := NewTracingDoer(http.DefaultClient).Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.SpanKind != trace.SpanKindClient || span.Status.Code.String() != "Error" {
+		t.Errorf("unexpected span: %+v", span)
+	}
+	if span.Parent.TraceID().String() != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("span not attached to the extracted trace: %v", span.Parent.TraceID())
+	}
+
+	propagated := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	if trace.SpanContextFromContext(propagated).SpanID() != span.SpanContext.SpanID() {
+		t.Errorf("trace context not propagated upstream: %v", header.Get("traceparent"))
+	}
+}
+
+func newTestTraces(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	traces := newTraces(sdktrace.NewSimpleSpanProcessor(exporter), sdktrace.AlwaysSample())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(traces.Shutdown)
+	return exporter
+}
@@ -0,0 +1,143 @@
+package recorders
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/rs/zerolog/log"
+)
+
+// New returns the HTTP request doer matching the mode: live requests are
+// done with the provided doer, recorded ones are also written in dir, and
+// replayed ones are only served from dir.
+func New(mode, dir string, doer HTTPRequestDoer) (HTTPRequestDoer, error) {
+	switch mode {
+	case constants.HTTPModeLive, "":
+		return doer, nil
+	case constants.HTTPModeRecord:
+		log.Warn().Msgf("Recording HTTP requests in %s", dir)
+		return &recorder{dir: dir, doer: doer}, nil
+	case constants.HTTPModeReplay:
+		log.Warn().Msgf("Replaying HTTP requests from %s", dir)
+		return &replayer{dir: dir}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownMode, mode)
+	}
+}
+
+func (recorder *recorder) Do(req *http.Request) (*http.Response, error) {
+	resp, err := recorder.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(fixture{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       encodeBody(body),
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(recorder.dir, dirPermission); err != nil {
+		return nil, err
+	}
+
+	fileName := getFileName(recorder.dir, req)
+	if err = os.WriteFile(fileName, data, filePermission); err != nil {
+		return nil, err
+	}
+
+	log.Debug().Msgf("HTTP request recorded in %s", fileName)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (replayer *replayer) Do(req *http.Request) (*http.Response, error) {
+	fileName := getFileName(replayer.dir, req)
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s %s", errFixtureNotFound, req.Method, req.URL.Path)
+		}
+		return nil, err
+	}
+
+	var record fixture
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+
+	body, err := decodeBody(record.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", record.StatusCode, http.StatusText(record.StatusCode)),
+		StatusCode:    record.StatusCode,
+		Proto:         req.Proto,
+		ProtoMajor:    req.ProtoMajor,
+		ProtoMinor:    req.ProtoMinor,
+		Header:        record.Header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// getFileName identifies a fixture by its method and path;
+// query parameters are not used by dofus-portals, they are ignored.
+func getFileName(dir string, req *http.Request) string {
+	name := strings.Trim(req.URL.Path, "/")
+	name = strings.NewReplacer("/", "_", ".", "_").Replace(name)
+	return filepath.Join(dir, fmt.Sprintf("%s_%s%s", req.Method, name, fixtureExtension))
+}
+
+// encodeBody keeps JSON bodies readable in fixtures so that they can be edited
+// by hand; other bodies are stored as JSON strings.
+func encodeBody(body []byte) json.RawMessage {
+	if len(body) > 0 && (body[0] == '{' || body[0] == '[') && json.Valid(body) {
+		return body
+	}
+
+	encoded, _ := json.Marshal(string(body))
+	return encoded
+}
+
+func decodeBody(body json.RawMessage) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+
+	if body[0] != '"' {
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, body); err != nil {
+			return nil, err
+		}
+		return compacted.Bytes(), nil
+	}
+
+	var value string
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
@@ -0,0 +1,102 @@
+package recorders
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaellybot/kaelly-portals/models/constants"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		statusCode int
+		body       string
+	}{
+		{name: "json body", path: "/external/v1/servers/agride/portals",
+			statusCode: http.StatusOK, body: `[{"server":"agride","dimension":"enutrosor"}]`},
+		{name: "empty body", path: "/external/v1/servers",
+			statusCode: http.StatusInternalServerError, body: ""},
+		{name: "malformed body", path: "/external/v1/dimensions",
+			statusCode: http.StatusOK, body: `{"server": [`},
+	}
+
+	dir := t.TempDir()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.statusCode)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer upstream.Close()
+
+			recordingDoer, err := New(constants.HTTPModeRecord, dir, upstream.Client())
+			if err != nil {
+				t.Fatalf("cannot build recorder: %v", err)
+			}
+			recorded := call(t, recordingDoer, upstream.URL+test.path)
+
+			replayingDoer, err := New(constants.HTTPModeReplay, dir, nil)
+			if err != nil {
+				t.Fatalf("cannot build replayer: %v", err)
+			}
+			upstream.Close()
+			replayed := call(t, replayingDoer, "http://unreachable.invalid"+test.path)
+
+			for _, body := range []string{recorded, replayed} {
+				if body != test.body {
+					t.Errorf("expected body %q, got %q", test.body, body)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayWithoutFixture(t *testing.T) {
+	doer, err := New(constants.HTTPModeReplay, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("cannot build replayer: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://localhost/unknown", nil)
+	if err != nil {
+		t.Fatalf("cannot build request: %v", err)
+	}
+
+	resp, err := doer.Do(req)
+	if err == nil {
+		resp.Body.Close()
+		t.Errorf("expected an error for missing fixture")
+	}
+}
+
+func TestUnknownMode(t *testing.T) {
+	if _, err := New("unknown", t.TempDir(), nil); err == nil {
+		t.Errorf("expected an error for unknown mode")
+	}
+}
+
+func call(t *testing.T, doer HTTPRequestDoer, url string) string {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("cannot build request: %v", err)
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("cannot read body: %v", err)
+	}
+
+	return string(body)
+}
@@ -0,0 +1,41 @@
+package recorders
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const (
+	fixtureExtension = ".json"
+	filePermission   = 0o600
+	dirPermission    = 0o750
+)
+
+var (
+	errUnknownMode     = errors.New("unknown HTTP mode")
+	errFixtureNotFound = errors.New("no fixture recorded for this request")
+)
+
+// HTTPRequestDoer performs HTTP requests; it matches the client interface
+// used by generated payloads.
+type HTTPRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type fixture struct {
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	StatusCode int             `json:"statusCode"`
+	Header     http.Header     `json:"header"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type recorder struct {
+	dir  string
+	doer HTTPRequestDoer
+}
+
+type replayer struct {
+	dir string
+}
@@ -3,18 +3,13 @@ package replies
 import (
 	amqp "github.com/kaellybot/kaelly-amqp"
 	"github.com/kaellybot/kaelly-portals/models/constants"
+	"github.com/kaellybot/kaelly-portals/utils/insights"
 	"github.com/rs/zerolog/log"
 )
 
 func SucceededAnswer(ctx amqp.Context, broker amqp.MessageBroker,
 	message *amqp.RabbitMQMessage) {
-	err := broker.Reply(message, ctx.CorrelationID, ctx.ReplyTo)
-	if err != nil {
-		log.Error().Err(err).
-			Str(constants.LogCorrelationID, ctx.CorrelationID).
-			Str(constants.LogReplyTo, ctx.ReplyTo).
-			Msgf("Cannot publish via broker, request ignored")
-	}
+	reply(ctx, broker, message)
 }
 
 func FailedAnswer(ctx amqp.Context, broker amqp.MessageBroker,
@@ -25,7 +20,20 @@ func FailedAnswer(ctx amqp.Context, broker amqp.MessageBroker,
 		Language: language,
 	}
 
-	err := broker.Reply(&message, ctx.CorrelationID, ctx.ReplyTo)
+	reply(ctx, broker, &message)
+}
+
+// Answer replies the message as is, its status being already set.
+func Answer(ctx amqp.Context, broker amqp.MessageBroker, message *amqp.RabbitMQMessage) {
+	reply(ctx, broker, message)
+}
+
+func reply(ctx amqp.Context, broker amqp.MessageBroker, message *amqp.RabbitMQMessage) {
+	_, span := insights.StartSpan(ctx, "amqp.reply",
+		insights.AttributeCorrelationID.String(ctx.CorrelationID))
+
+	err := broker.Reply(message, ctx.CorrelationID, ctx.ReplyTo)
+	insights.EndSpan(span, err)
 	if err != nil {
 		log.Error().Err(err).
 			Str(constants.LogCorrelationID, ctx.CorrelationID).
@@ -0,0 +1,22 @@
+package retries
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Do calls try until it succeeds or the policy retries are exhausted, returning the last error.
+func Do(name string, policy Policy, try func() error) error {
+	delay := policy.Delay
+	err := try()
+	for retry := 1; err != nil && retry <= policy.Retries; retry++ {
+		log.Warn().Err(err).
+			Msgf("%s unavailable, retry %d/%d in %v", name, retry, policy.Retries, delay)
+		time.Sleep(delay)
+		delay = min(delay*backoffFactor, maxDelay)
+		err = try()
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package retries
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+func TestDo(t *testing.T) {
+	tests := []struct {
+		name          string
+		retries       int
+		failures      int
+		expectedCalls int
+		expectedError error
+	}{
+		{name: "first try", retries: 3, failures: 0, expectedCalls: 1},
+		{name: "after retries", retries: 3, failures: 2, expectedCalls: 3},
+		{name: "exhausted", retries: 3, failures: 5, expectedCalls: 4, expectedError: errUnavailable},
+		{name: "no retry", retries: 0, failures: 1, expectedCalls: 1, expectedError: errUnavailable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			err := Do("test", Policy{Retries: test.retries, Delay: time.Millisecond}, func() error {
+				calls++
+				if calls <= test.failures {
+					return errUnavailable
+				}
+				return nil
+			})
+
+			if !errors.Is(err, test.expectedError) {
+				t.Errorf("expected error %v, got %v", test.expectedError, err)
+			}
+			if calls != test.expectedCalls {
+				t.Errorf("expected %d calls, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
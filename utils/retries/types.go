@@ -0,0 +1,15 @@
+package retries
+
+import "time"
+
+const (
+	backoffFactor = 2
+	maxDelay      = 30 * time.Second
+)
+
+// Policy of retries: the delay before the first retry is doubled after each failure,
+// up to maxDelay.
+type Policy struct {
+	Retries int
+	Delay   time.Duration
+}